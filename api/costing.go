@@ -0,0 +1,360 @@
+package main
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ==================== INGREDIENTS & RECIPES ====================
+
+// RecipeRequest replaces the recipe of a product or variant
+type RecipeRequest struct {
+	VariantID *string `json:"variant_id"`
+	Items     []struct {
+		IngredientID string  `json:"ingredient_id"`
+		Quantity     float64 `json:"quantity"`
+	} `json:"items"`
+}
+
+// Cost of one unit of each order item, the variant recipe when it has one
+const orderItemUnitCostSQL = `
+	COALESCE(
+		(SELECT SUM(r.quantity * i.cost_per_unit) FROM recipes r
+			JOIN ingredients i ON i.id = r.ingredient_id
+			WHERE r.product_id = oi.product_id AND oi.variant_id IS NOT NULL AND r.variant_id = oi.variant_id),
+		(SELECT SUM(r.quantity * i.cost_per_unit) FROM recipes r
+			JOIN ingredients i ON i.id = r.ingredient_id
+			WHERE r.product_id = oi.product_id AND r.variant_id IS NULL),
+		0
+	)`
+
+// Compute and freeze the cost of goods sold of an order with the current
+// ingredient costs. Runs once, later price changes do not rewrite history.
+func computeOrderCOGS(orderID string) {
+	if !db.Ready() {
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var pending int64
+		tx.Table("orders").Where("id = ? AND cogs_computed_at IS NULL", orderID).Count(&pending)
+		if pending == 0 {
+			return nil
+		}
+
+		if err := tx.Exec(`
+			UPDATE order_items oi SET cogs = oi.quantity * `+orderItemUnitCostSQL+`
+			WHERE oi.order_id = ?
+		`, orderID).Error; err != nil {
+			return err
+		}
+
+		return tx.Exec(`
+			UPDATE orders SET
+				cogs = (SELECT COALESCE(SUM(cogs), 0) FROM order_items WHERE order_id = orders.id),
+				cogs_computed_at = NOW()
+			WHERE id = ?
+		`, orderID).Error
+	})
+	if err != nil {
+		log.Printf("❌ Failed to compute COGS for order %s: %v", orderID, err)
+	}
+}
+
+// Validate and clean an ingredient
+func validateIngredient(ingredient *models.Ingredient) string {
+	ingredient.Name = strings.TrimSpace(ingredient.Name)
+	ingredient.Unit = strings.TrimSpace(ingredient.Unit)
+	if ingredient.Name == "" || ingredient.Unit == "" {
+		return "Name and unit are required"
+	}
+	if ingredient.CostPerUnit < 0 {
+		return "Cost per unit must not be negative"
+	}
+	return ""
+}
+
+func registerCostingRoutes(app *fiber.App) {
+	// Admin: List ingredients
+	app.Get("/api/admin/ingredients", func(c *fiber.Ctx) error {
+		var ingredients []models.Ingredient
+		if err := db.DB.Order("name ASC").Find(&ingredients).Error; err != nil {
+			log.Printf("Error fetching ingredients: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to fetch ingredients",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    ingredients,
+		})
+	})
+
+	// Admin: Create ingredient
+	app.Post("/api/admin/ingredients", func(c *fiber.Ctx) error {
+		var ingredient models.Ingredient
+		if err := c.BodyParser(&ingredient); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+		if msg := validateIngredient(&ingredient); msg != "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": msg,
+			})
+		}
+
+		ingredient.ID = ""
+		if err := db.DB.Create(&ingredient).Error; err != nil {
+			log.Printf("Error creating ingredient: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to create ingredient, the name may already exist",
+			})
+		}
+
+		return c.Status(201).JSON(fiber.Map{
+			"success": true,
+			"data":    ingredient,
+			"message": "Ingredient created",
+		})
+	})
+
+	// Admin: Update ingredient, new costs apply to orders completed from now on
+	app.Put("/api/admin/ingredients/:id", func(c *fiber.Ctx) error {
+		var ingredient models.Ingredient
+		if err := db.DB.First(&ingredient, "id = ?", c.Params("id")).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Ingredient not found",
+			})
+		}
+
+		var req models.Ingredient
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+		if msg := validateIngredient(&req); msg != "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": msg,
+			})
+		}
+
+		err := db.DB.Model(&ingredient).Updates(map[string]interface{}{
+			"name":          req.Name,
+			"unit":          req.Unit,
+			"cost_per_unit": req.CostPerUnit,
+			"updated_at":    time.Now(),
+		}).Error
+		if err != nil {
+			log.Printf("Error updating ingredient: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to update ingredient",
+			})
+		}
+		db.DB.First(&ingredient, "id = ?", ingredient.ID)
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    ingredient,
+			"message": "Ingredient updated",
+		})
+	})
+
+	// Admin: Delete ingredient, refused while a recipe uses it
+	app.Delete("/api/admin/ingredients/:id", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		var used int64
+		db.DB.Model(&models.Recipe{}).Where("ingredient_id = ?", id).Count(&used)
+		if used > 0 {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Ingredient is used in recipes",
+			})
+		}
+
+		result := db.DB.Delete(&models.Ingredient{}, "id = ?", id)
+		if result.Error != nil {
+			log.Printf("Error deleting ingredient: %v", result.Error)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to delete ingredient",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Ingredient not found",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Ingredient deleted",
+		})
+	})
+
+	// Admin: Recipes of a product and its variants, with the unit cost of each
+	app.Get("/api/admin/products/:id/recipe", func(c *fiber.Ctx) error {
+		var product models.Product
+		if err := db.DB.Preload("Variants").First(&product, "id = ?", c.Params("id")).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Product not found",
+			})
+		}
+
+		var recipes []models.Recipe
+		db.DB.Preload("Ingredient").Where("product_id = ?", product.ID).Order("created_at ASC").Find(&recipes)
+
+		// Unit cost per recipe, "" is the product recipe
+		costs := map[string]float64{}
+		for _, r := range recipes {
+			key := ""
+			if r.VariantID != nil {
+				key = *r.VariantID
+			}
+			if r.Ingredient != nil {
+				costs[key] += r.Quantity * r.Ingredient.CostPerUnit
+			}
+		}
+
+		unitCosts := []fiber.Map{{
+			"variant_id": nil,
+			"name":       product.Name,
+			"price":      product.Price,
+			"unit_cost":  costs[""],
+		}}
+		for _, v := range product.Variants {
+			cost, ok := costs[v.ID]
+			if !ok {
+				cost = costs[""]
+			}
+			unitCosts = append(unitCosts, fiber.Map{
+				"variant_id": v.ID,
+				"name":       v.Name,
+				"price":      v.Price,
+				"unit_cost":  cost,
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data": fiber.Map{
+				"product_id": product.ID,
+				"recipes":    recipes,
+				"unit_costs": unitCosts,
+			},
+		})
+	})
+
+	// Admin: Replace the recipe of a product, or of one variant with variant_id
+	app.Put("/api/admin/products/:id/recipe", func(c *fiber.Ctx) error {
+		var product models.Product
+		if err := db.DB.First(&product, "id = ?", c.Params("id")).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Product not found",
+			})
+		}
+
+		var req RecipeRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+		if req.VariantID != nil && *req.VariantID == "" {
+			req.VariantID = nil
+		}
+		if req.VariantID != nil {
+			var count int64
+			db.DB.Model(&models.ProductVariant{}).Where("id = ? AND product_id = ?", *req.VariantID, product.ID).Count(&count)
+			if count == 0 {
+				return c.Status(404).JSON(fiber.Map{
+					"success": false,
+					"message": "Variant not found",
+				})
+			}
+		}
+
+		seen := map[string]bool{}
+		var recipes []models.Recipe
+		for _, item := range req.Items {
+			if item.Quantity <= 0 {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": "Quantity must be positive",
+				})
+			}
+			if seen[item.IngredientID] {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": "Each ingredient can only be listed once",
+				})
+			}
+			seen[item.IngredientID] = true
+
+			var count int64
+			db.DB.Model(&models.Ingredient{}).Where("id = ?", item.IngredientID).Count(&count)
+			if count == 0 {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": "Ingredient not found: " + item.IngredientID,
+				})
+			}
+			recipes = append(recipes, models.Recipe{
+				ProductID:    product.ID,
+				VariantID:    req.VariantID,
+				IngredientID: item.IngredientID,
+				Quantity:     item.Quantity,
+			})
+		}
+
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			query := tx.Where("product_id = ?", product.ID)
+			if req.VariantID != nil {
+				query = query.Where("variant_id = ?", *req.VariantID)
+			} else {
+				query = query.Where("variant_id IS NULL")
+			}
+			if err := query.Delete(&models.Recipe{}).Error; err != nil {
+				return err
+			}
+			if len(recipes) == 0 {
+				return nil
+			}
+			return tx.Create(&recipes).Error
+		})
+		if err != nil {
+			log.Printf("Error saving recipe: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to save recipe",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    recipes,
+			"message": "Recipe saved",
+		})
+	})
+}
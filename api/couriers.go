@@ -0,0 +1,792 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/pdf"
+	"scaff-food-backend/internal/validate"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ==================== COURIERS & DELIVERY BATCHES ====================
+
+// DeliveryBatchRequest creates or changes a batch. OrderIDs is the route in
+// stop order and replaces the orders of the batch when sent.
+type DeliveryBatchRequest struct {
+	Name         string   `json:"name"`
+	CourierID    *string  `json:"courier_id"`
+	DeliveryDate string   `json:"delivery_date"`
+	Notes        string   `json:"notes"`
+	OrderIDs     []string `json:"order_ids"`
+}
+
+// DeliveryStop is an order on a route with its items
+type DeliveryStop struct {
+	models.Order
+	Items []models.OrderItem `json:"items"`
+}
+
+// DeliveryBatchView is a batch with its courier and stops
+type DeliveryBatchView struct {
+	models.DeliveryBatch
+	Courier *models.User   `json:"courier,omitempty"`
+	Stops   []DeliveryStop `json:"stops"`
+}
+
+// Middleware: the session must belong to a courier
+func requireCourier(c *fiber.Ctx) error {
+	session := auth.Current(c)
+	if session == nil || session.Role != auth.RoleCourier {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Hanya kurir yang dapat mengakses halaman ini.",
+		})
+	}
+	return c.Next()
+}
+
+// Answer a delivery update that lost the race against another change of the
+// order, its side effects already ran for that change
+func deliveryChanged(c *fiber.Ctx) error {
+	return c.Status(409).JSON(fiber.Map{
+		"success": false,
+		"message": "Pesanan sudah diubah. Muat ulang dan coba lagi.",
+	})
+}
+
+// Payment methods paid in cash to the courier
+func isCashOnDelivery(method string) bool {
+	switch strings.ToLower(strings.TrimSpace(method)) {
+	case "cod", "cash":
+		return true
+	}
+	return false
+}
+
+// Find a courier by ID
+func findCourier(id string) (*models.User, error) {
+	var courier models.User
+	if err := db.DB.Where("id = ? AND role = ?", id, auth.RoleCourier).First(&courier).Error; err != nil {
+		return nil, err
+	}
+	return &courier, nil
+}
+
+// Orders matching the condition with their items, in route order
+func loadDeliveryStops(where string, args ...interface{}) ([]DeliveryStop, error) {
+	var orders []models.Order
+	if err := db.DB.Where(where, args...).
+		Order("delivery_sequence ASC, created_at ASC").
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	stops := make([]DeliveryStop, len(orders))
+	if len(orders) == 0 {
+		return stops, nil
+	}
+
+	ids := make([]string, len(orders))
+	for i, order := range orders {
+		ids[i] = order.ID
+	}
+	var items []models.OrderItem
+	if err := db.DB.Where("order_id IN ?", ids).Order("created_at ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+	byOrder := make(map[string][]models.OrderItem)
+	for _, item := range items {
+		byOrder[item.OrderID] = append(byOrder[item.OrderID], item)
+	}
+	for i, order := range orders {
+		stops[i] = DeliveryStop{Order: order, Items: byOrder[order.ID]}
+		if stops[i].Items == nil {
+			stops[i].Items = []models.OrderItem{}
+		}
+	}
+	return stops, nil
+}
+
+// A batch with its courier and stops
+func loadDeliveryBatch(id string) (*DeliveryBatchView, error) {
+	var view DeliveryBatchView
+	if err := db.DB.First(&view.DeliveryBatch, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	if view.CourierID != nil {
+		view.Courier, _ = findCourier(*view.CourierID)
+	}
+	stops, err := loadDeliveryStops("delivery_batch_id = ?", view.ID)
+	if err != nil {
+		return nil, err
+	}
+	view.Stops = stops
+	return &view, nil
+}
+
+// Put the orders on the batch in route order and hand them to its courier.
+// Orders previously on the batch but not in orderIDs are taken off it.
+func assignBatchOrders(tx *gorm.DB, batch models.DeliveryBatch, orderIDs []string) error {
+	if err := tx.Model(&models.Order{}).
+		Where("delivery_batch_id = ? AND id NOT IN ?", batch.ID, append([]string{"00000000-0000-0000-0000-000000000000"}, orderIDs...)).
+		Updates(map[string]interface{}{
+			"delivery_batch_id": nil,
+			"courier_id":        nil,
+			"delivery_sequence": 0,
+		}).Error; err != nil {
+		return err
+	}
+	for i, orderID := range orderIDs {
+		if err := tx.Model(&models.Order{}).Where("id = ?", orderID).Updates(map[string]interface{}{
+			"delivery_batch_id": batch.ID,
+			"courier_id":        batch.CourierID,
+			"delivery_sequence": i + 1,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Check the request orders exist and can still be delivered
+func validateBatchOrders(orderIDs []string) string {
+	seen := make(map[string]bool)
+	for _, id := range orderIDs {
+		if seen[id] {
+			return "Each order can only be on the route once"
+		}
+		seen[id] = true
+	}
+	if len(orderIDs) == 0 {
+		return ""
+	}
+
+	var orders []models.Order
+	db.DB.Select("id, order_number, order_status").Where("id IN ?", orderIDs).Find(&orders)
+	if len(orders) != len(orderIDs) {
+		return "One or more orders were not found"
+	}
+	for _, order := range orders {
+		if order.OrderStatus == "completed" || models.IsCancelledStatus(order.OrderStatus) {
+			return fmt.Sprintf("Order %s is already %s", order.OrderNumber, order.OrderStatus)
+		}
+	}
+	return ""
+}
+
+// Route sheet of a batch as a printable A4 PDF, one block per stop with
+// what the courier needs at the door
+func deliveryRouteSheetPDF(view *DeliveryBatchView) []byte {
+	const (
+		margin    = 40.0
+		right     = 555.0
+		fontSize  = 10.0
+		lineH     = 13.0
+		colDetail = margin + 30
+	)
+
+	courierName := "-"
+	if view.Courier != nil {
+		courierName = view.Courier.Name
+		if courierName == "" {
+			courierName = view.Courier.Email
+		}
+		if view.Courier.Phone != "" {
+			courierName += " (" + view.Courier.Phone + ")"
+		}
+	}
+	date := "-"
+	if view.DeliveryDate != nil {
+		date = view.DeliveryDate.Format("02-01-2006")
+	}
+	collect := 0.0
+	for _, stop := range view.Stops {
+		if stop.PaymentStatus != "paid" {
+			collect += stop.Total
+		}
+	}
+
+	doc := pdf.New()
+	var page *pdf.Page
+	y := 0.0
+
+	newPage := func() {
+		page = doc.AddPage()
+		page.Text(margin, 50, 18, true, "Rute Pengiriman SCAFF*FOOD")
+		page.Text(margin, 68, 10, false, fmt.Sprintf("%s   |   Kurir: %s   |   Tanggal: %s", view.Name, courierName, date))
+		page.TextRight(right, 68, 8, false, fmt.Sprintf("Dicetak %s  -  hal. %d", time.Now().Format("02-01-2006 15:04"), doc.PageCount()))
+		page.Line(margin, 76, right, 76, 1)
+		y = 96
+	}
+	newPage()
+
+	page.Text(margin, y, 11, false, fmt.Sprintf("%d alamat   |   Tagihan COD: %s", len(view.Stops), formatRupiah(collect)))
+	y += 24
+	if len(view.Stops) == 0 {
+		page.Text(margin, y, 11, false, "Belum ada pesanan di rute ini.")
+	}
+
+	for i, stop := range view.Stops {
+		var lines []string
+		lines = append(lines, pdf.Wrap("Alamat: "+stop.DeliveryAddress+" ("+stop.DeliveryLocation+")", fontSize, false, right-colDetail)...)
+		for _, item := range stop.Items {
+			lines = append(lines, pdf.Fit(fmt.Sprintf("%dx %s", item.Quantity, item.ProductName), fontSize, false, right-colDetail))
+		}
+		payment := fmt.Sprintf("Bayar: %s (%s)", stop.PaymentMethod, stop.PaymentStatus)
+		if stop.PaymentStatus != "paid" {
+			payment += "   |   TAGIH " + formatRupiah(stop.Total)
+		}
+		lines = append(lines, payment)
+
+		height := 18 + float64(len(lines))*lineH + 34
+		if y+height > doc.Height-margin {
+			newPage()
+		}
+
+		// Stop number and a checkbox to tick off once delivered
+		page.FillRect(margin, y, right-margin, 18, 0.9)
+		page.Text(margin+4, y+12.5, 11, true, fmt.Sprintf("%d.", i+1))
+		page.Text(colDetail, y+12.5, 11, true, pdf.Fit(fmt.Sprintf("%s  -  %s  -  %s", stop.OrderNumber, stop.CustomerName, stop.CustomerPhone), 11, true, right-colDetail-24))
+		page.StrokeRect(right-16, y+4, 10, 10, 0.7)
+		y += 18 + lineH
+
+		for _, line := range lines {
+			page.Text(colDetail, y, fontSize, false, line)
+			y += lineH
+		}
+		y += 4
+		page.Text(colDetail, y+8, 8, false, "Diterima oleh: ______________________   Jam: ________")
+		y += 30
+	}
+
+	return doc.Bytes()
+}
+
+func registerCourierRoutes(app *fiber.App) {
+	// Admin: List couriers
+	app.Get("/api/admin/couriers", func(c *fiber.Ctx) error {
+		var couriers []models.User
+		if err := db.DB.Where("role = ?", auth.RoleCourier).Order("name ASC").Find(&couriers).Error; err != nil {
+			log.Printf("Error fetching couriers: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to fetch couriers",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    couriers,
+		})
+	})
+
+	// Admin: Add a courier, they log in with the admin OTP flow
+	app.Post("/api/admin/couriers", func(c *fiber.Ctx) error {
+		var req struct {
+			Name  string `json:"name"`
+			Email string `json:"email"`
+			Phone string `json:"phone"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+
+		courier := models.User{
+			Name:  strings.TrimSpace(req.Name),
+			Email: validate.NormalizeEmail(req.Email),
+			Role:  auth.RoleCourier,
+		}
+		if courier.Name == "" || courier.Email == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Name and email are required",
+			})
+		}
+		if req.Phone != "" {
+			phone, ok := validate.NormalizePhone(req.Phone)
+			if !ok {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": "Invalid phone number",
+				})
+			}
+			courier.Phone = phone
+		}
+
+		var existing int64
+		db.DB.Model(&models.User{}).Where("email = ?", courier.Email).Count(&existing)
+		if existing > 0 {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "A user with this email already exists",
+			})
+		}
+
+		if err := db.DB.Create(&courier).Error; err != nil {
+			log.Printf("Error creating courier: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to create courier",
+			})
+		}
+
+		return c.Status(201).JSON(fiber.Map{
+			"success": true,
+			"data":    courier,
+			"message": "Courier created",
+		})
+	})
+
+	// Admin: Remove a courier, their open deliveries become unassigned
+	app.Delete("/api/admin/couriers/:id", func(c *fiber.Ctx) error {
+		courier, err := findCourier(c.Params("id"))
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Courier not found",
+			})
+		}
+
+		if err := db.DB.Delete(courier).Error; err != nil {
+			log.Printf("Error deleting courier: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to delete courier",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Courier deleted",
+		})
+	})
+
+	// Admin: List delivery batches (?date=YYYY-MM-DD)
+	app.Get("/api/admin/delivery-batches", func(c *fiber.Ctx) error {
+		type batchSummary struct {
+			models.DeliveryBatch
+			CourierName string `json:"courier_name"`
+			Orders      int    `json:"orders"`
+			Completed   int    `json:"completed"`
+		}
+
+		query := db.DB.Table("delivery_batches b").
+			Select(`b.*, COALESCE(u.name, '') AS courier_name,
+				COUNT(o.id) AS orders,
+				COUNT(o.id) FILTER (WHERE o.order_status = 'completed') AS completed`).
+			Joins("LEFT JOIN users u ON u.id = b.courier_id").
+			Joins("LEFT JOIN orders o ON o.delivery_batch_id = b.id").
+			Group("b.id, u.name").
+			Order("b.delivery_date DESC, b.created_at ASC")
+		if date := c.Query("date"); date != "" {
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": "Date must be in YYYY-MM-DD format",
+				})
+			}
+			query = query.Where("b.delivery_date = ?", date)
+		}
+
+		var batches []batchSummary
+		if err := query.Scan(&batches).Error; err != nil {
+			log.Printf("Error fetching delivery batches: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to fetch delivery batches",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    batches,
+		})
+	})
+
+	// Admin: Get a delivery batch with its stops
+	app.Get("/api/admin/delivery-batches/:id", func(c *fiber.Ctx) error {
+		view, err := loadDeliveryBatch(c.Params("id"))
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Delivery batch not found",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    view,
+		})
+	})
+
+	// Admin: Route sheet of a delivery batch as PDF
+	app.Get("/api/admin/delivery-batches/:id/sheet", func(c *fiber.Ctx) error {
+		view, err := loadDeliveryBatch(c.Params("id"))
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Delivery batch not found",
+			})
+		}
+
+		c.Set(fiber.HeaderContentType, "application/pdf")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="rute-%s.pdf"`, view.ID))
+		return c.Send(deliveryRouteSheetPDF(view))
+	})
+
+	// Admin: Create a delivery batch and assign its orders to the courier
+	app.Post("/api/admin/delivery-batches", func(c *fiber.Ctx) error {
+		var req DeliveryBatchRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+
+		batch := models.DeliveryBatch{
+			Name:  strings.TrimSpace(req.Name),
+			Notes: strings.TrimSpace(req.Notes),
+		}
+		if req.DeliveryDate != "" {
+			date, err := time.Parse("2006-01-02", req.DeliveryDate)
+			if err != nil {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": "Delivery date must be in YYYY-MM-DD format",
+				})
+			}
+			batch.DeliveryDate = &date
+		}
+		if batch.Name == "" {
+			batch.Name = "Rute " + time.Now().Format("02-01-2006 15:04")
+			if batch.DeliveryDate != nil {
+				batch.Name = "Rute " + batch.DeliveryDate.Format("02-01-2006")
+			}
+		}
+		if req.CourierID != nil && *req.CourierID != "" {
+			if _, err := findCourier(*req.CourierID); err != nil {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": "Courier not found",
+				})
+			}
+			batch.CourierID = req.CourierID
+		}
+		if msg := validateBatchOrders(req.OrderIDs); msg != "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": msg,
+			})
+		}
+
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&batch).Error; err != nil {
+				return err
+			}
+			return assignBatchOrders(tx, batch, req.OrderIDs)
+		})
+		if err != nil {
+			log.Printf("Error creating delivery batch: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to create delivery batch",
+			})
+		}
+
+		view, _ := loadDeliveryBatch(batch.ID)
+		return c.Status(201).JSON(fiber.Map{
+			"success": true,
+			"data":    view,
+			"message": "Delivery batch created",
+		})
+	})
+
+	// Admin: Change a delivery batch, reassigning its courier or its route
+	app.Put("/api/admin/delivery-batches/:id", func(c *fiber.Ctx) error {
+		var batch models.DeliveryBatch
+		if err := db.DB.First(&batch, "id = ?", c.Params("id")).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Delivery batch not found",
+			})
+		}
+
+		var req DeliveryBatchRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+
+		if name := strings.TrimSpace(req.Name); name != "" {
+			batch.Name = name
+		}
+		if req.Notes != "" {
+			batch.Notes = strings.TrimSpace(req.Notes)
+		}
+		if req.DeliveryDate != "" {
+			date, err := time.Parse("2006-01-02", req.DeliveryDate)
+			if err != nil {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": "Delivery date must be in YYYY-MM-DD format",
+				})
+			}
+			batch.DeliveryDate = &date
+		}
+		if req.CourierID != nil {
+			batch.CourierID = nil
+			if *req.CourierID != "" {
+				if _, err := findCourier(*req.CourierID); err != nil {
+					return c.Status(400).JSON(fiber.Map{
+						"success": false,
+						"message": "Courier not found",
+					})
+				}
+				batch.CourierID = req.CourierID
+			}
+		}
+		if req.OrderIDs != nil {
+			if msg := validateBatchOrders(req.OrderIDs); msg != "" {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": msg,
+				})
+			}
+		}
+
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Model(&batch).Select("name", "notes", "delivery_date", "courier_id", "updated_at").Updates(&batch).Error; err != nil {
+				return err
+			}
+			if req.OrderIDs != nil {
+				return assignBatchOrders(tx, batch, req.OrderIDs)
+			}
+			// Undelivered orders follow the batch to its new courier
+			return tx.Model(&models.Order{}).
+				Where("delivery_batch_id = ? AND order_status IN ?", batch.ID, models.ActiveOrderStatuses).
+				Update("courier_id", batch.CourierID).Error
+		})
+		if err != nil {
+			log.Printf("Error updating delivery batch: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to update delivery batch",
+			})
+		}
+
+		view, _ := loadDeliveryBatch(batch.ID)
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    view,
+			"message": "Delivery batch updated",
+		})
+	})
+
+	// Admin: Delete a delivery batch, its orders become unassigned
+	app.Delete("/api/admin/delivery-batches/:id", func(c *fiber.Ctx) error {
+		var batch models.DeliveryBatch
+		if err := db.DB.First(&batch, "id = ?", c.Params("id")).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Delivery batch not found",
+			})
+		}
+
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			if err := assignBatchOrders(tx, batch, []string{}); err != nil {
+				return err
+			}
+			return tx.Delete(&batch).Error
+		})
+		if err != nil {
+			log.Printf("Error deleting delivery batch: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to delete delivery batch",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Delivery batch deleted",
+		})
+	})
+
+	courier := app.Group("/api/courier/me", auth.RequireSession, requireCourier)
+
+	// Courier: Deliveries assigned to me, open ones unless ?status=all
+	courier.Get("/deliveries", func(c *fiber.Ctx) error {
+		session := auth.Current(c)
+		where, args := "courier_id = ? AND order_status IN ?", []interface{}{session.UserID, models.ActiveOrderStatuses}
+		if c.Query("status") == "all" {
+			where, args = "courier_id = ?", []interface{}{session.UserID}
+		}
+		if date := c.Query("date"); date != "" {
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": "Date must be in YYYY-MM-DD format",
+				})
+			}
+			where += " AND delivery_date = ?"
+			args = append(args, date)
+		}
+
+		stops, err := loadDeliveryStops(where, args...)
+		if err != nil {
+			log.Printf("Error fetching courier deliveries: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to fetch deliveries",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    stops,
+		})
+	})
+
+	// Courier: Start a delivery, the order is on its way
+	courier.Post("/deliveries/:id/start", func(c *fiber.Ctx) error {
+		session := auth.Current(c)
+		var order models.Order
+		if err := db.DB.First(&order, "id = ? AND courier_id = ?", c.Params("id"), session.UserID).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Pengiriman tidak ditemukan.",
+			})
+		}
+		if order.OrderStatus != "pending" && order.OrderStatus != "processing" {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("Pesanan sudah berstatus %s.", order.OrderStatus),
+			})
+		}
+
+		oldStatus := order.OrderStatus
+		result := db.DB.Model(&models.Order{}).
+			Where("id = ? AND order_status = ?", order.ID, oldStatus).
+			Update("order_status", "on_delivery")
+		if result.Error != nil {
+			log.Printf("Error starting delivery %s: %v", order.OrderNumber, result.Error)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to update delivery",
+			})
+		}
+		if result.RowsAffected != 1 {
+			return deliveryChanged(c)
+		}
+		order.OrderStatus = "on_delivery"
+		afterOrderStatusChange(order, oldStatus, order.PaymentStatus, false, session.Email)
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    order,
+			"message": "Pengiriman dimulai",
+		})
+	})
+
+	// Courier: Complete a delivery with a photo (uploaded via /api/upload) and a note
+	courier.Post("/deliveries/:id/complete", func(c *fiber.Ctx) error {
+		var req struct {
+			DeliveryPhoto string `json:"delivery_photo"`
+			DeliveryNote  string `json:"delivery_note"`
+			PaymentPaid   bool   `json:"payment_paid"`
+		}
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+		req.DeliveryPhoto = strings.TrimSpace(req.DeliveryPhoto)
+		if req.DeliveryPhoto == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Foto pengiriman wajib diisi.",
+			})
+		}
+
+		session := auth.Current(c)
+		var order models.Order
+		if err := db.DB.First(&order, "id = ? AND courier_id = ?", c.Params("id"), session.UserID).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Pengiriman tidak ditemukan.",
+			})
+		}
+		if order.OrderStatus == "completed" || models.IsCancelledStatus(order.OrderStatus) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("Pesanan sudah berstatus %s.", order.OrderStatus),
+			})
+		}
+
+		// Only cash on delivery is collected at the door
+		if req.PaymentPaid && order.PaymentStatus != "paid" &&
+			(!isCashOnDelivery(order.PaymentMethod) || order.PaymentStatus != "pending") {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Hanya pesanan COD yang belum dibayar dapat ditandai lunas oleh kurir.",
+			})
+		}
+
+		oldStatus, oldPaymentStatus := order.OrderStatus, order.PaymentStatus
+		now := time.Now()
+		updateData := map[string]interface{}{
+			"order_status":   "completed",
+			"delivery_photo": req.DeliveryPhoto,
+			"delivery_note":  strings.TrimSpace(req.DeliveryNote),
+			"delivered_at":   now,
+		}
+		// Cash collected at the door
+		if req.PaymentPaid {
+			updateData["payment_status"] = "paid"
+		}
+		result := db.DB.Model(&models.Order{}).
+			Where("id = ? AND order_status = ? AND payment_status = ?", order.ID, oldStatus, oldPaymentStatus).
+			Updates(updateData)
+		if result.Error != nil {
+			log.Printf("Error completing delivery %s: %v", order.OrderNumber, result.Error)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to complete delivery",
+			})
+		}
+		if result.RowsAffected != 1 {
+			return deliveryChanged(c)
+		}
+
+		order.OrderStatus = "completed"
+		order.DeliveryPhoto = req.DeliveryPhoto
+		order.DeliveryNote = strings.TrimSpace(req.DeliveryNote)
+		order.DeliveredAt = &now
+		if req.PaymentPaid {
+			order.PaymentStatus = "paid"
+		}
+		log.Printf("📦 Order %s delivered by %s", order.OrderNumber, session.Email)
+		afterOrderStatusChange(order, oldStatus, oldPaymentStatus, true, session.Email)
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    order,
+			"message": "Pengiriman selesai",
+		})
+	})
+}
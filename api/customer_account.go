@@ -0,0 +1,281 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/validate"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ==================== CUSTOMER ACCOUNT ====================
+
+// Maximum saved addresses per customer
+const maxCustomerAddresses = 10
+
+// Sanitize and validate a saved address
+func validateCustomerAddress(address *models.CustomerAddress) error {
+	address.Label = validate.SanitizeString(address.Label)
+	address.RecipientName = validate.SanitizeString(address.RecipientName)
+	address.RecipientPhone = validate.SanitizeString(address.RecipientPhone)
+	address.Address = validate.SanitizeString(address.Address)
+	address.DeliveryLocation = validate.SanitizeString(address.DeliveryLocation)
+
+	if address.Address == "" {
+		return fmt.Errorf("address is required")
+	}
+	if len(address.Address) > 500 {
+		return fmt.Errorf("address is too long")
+	}
+	if len(address.Label) > 50 || len(address.RecipientName) > 100 {
+		return fmt.Errorf("label or recipient name is too long")
+	}
+	if address.RecipientPhone != "" {
+		phone, ok := validate.NormalizePhone(address.RecipientPhone)
+		if !ok {
+			return fmt.Errorf("invalid phone number format")
+		}
+		address.RecipientPhone = phone
+	}
+	if address.DeliveryLocation == "" {
+		address.DeliveryLocation = "TB"
+	}
+	if address.DeliveryLocation != "TB" && address.DeliveryLocation != "Luar TB" {
+		return fmt.Errorf("delivery location must be TB or Luar TB")
+	}
+	return nil
+}
+
+// Middleware: only customer sessions backed by a users row may use /api/me
+func requireCustomer(c *fiber.Ctx) error {
+	session := auth.Current(c)
+	if session == nil || session.UserID == "" {
+		return c.Status(401).JSON(fiber.Map{
+			"success": false,
+			"message": "Sesi tidak valid. Silakan verifikasi ulang.",
+		})
+	}
+	// Admin and courier sessions carry their own role and a user id too
+	if session.Role != auth.RoleCustomer {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Hanya pelanggan yang dapat mengakses halaman ini.",
+		})
+	}
+	return c.Next()
+}
+
+func registerCustomerAccountRoutes(app *fiber.App) {
+	me := app.Group("/api/me", auth.RequireSession, requireCustomer)
+
+	// Get own profile
+	me.Get("/", func(c *fiber.Ctx) error {
+		var user models.User
+		if err := db.DB.First(&user, "id = ?", auth.Current(c).UserID).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "User not found",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    user,
+		})
+	})
+
+	// Get own order history
+	me.Get("/orders", func(c *fiber.Ctx) error {
+		var orders []models.Order
+		result := db.DB.Where("user_id = ?", auth.Current(c).UserID).Order("created_at DESC").Find(&orders)
+		if result.Error != nil {
+			log.Printf("Error fetching customer orders: %v", result.Error)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to fetch orders",
+			})
+		}
+
+		ordersWithItems := []models.OrderWithItems{}
+		for _, order := range orders {
+			var items []models.OrderItem
+			db.DB.Where("order_id = ?", order.ID).Find(&items)
+
+			ordersWithItems = append(ordersWithItems, models.OrderWithItems{
+				Order: order,
+				Items: items,
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    ordersWithItems,
+		})
+	})
+
+	// Get saved addresses
+	me.Get("/addresses", func(c *fiber.Ctx) error {
+		var addresses []models.CustomerAddress
+		result := db.DB.Where("user_id = ?", auth.Current(c).UserID).
+			Order("is_default DESC, created_at ASC").
+			Find(&addresses)
+		if result.Error != nil {
+			log.Printf("Error fetching addresses: %v", result.Error)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to fetch addresses",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    addresses,
+		})
+	})
+
+	// Save a new address
+	me.Post("/addresses", func(c *fiber.Ctx) error {
+		userID := auth.Current(c).UserID
+
+		var address models.CustomerAddress
+		if err := c.BodyParser(&address); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+		if err := validateCustomerAddress(&address); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("Validation error: %v", err),
+			})
+		}
+
+		var count int64
+		db.DB.Model(&models.CustomerAddress{}).Where("user_id = ?", userID).Count(&count)
+		if count >= maxCustomerAddresses {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("Maksimal %d alamat tersimpan", maxCustomerAddresses),
+			})
+		}
+
+		address.ID = ""
+		address.UserID = userID
+		// First address becomes the default one
+		if count == 0 {
+			address.IsDefault = true
+		}
+		if address.IsDefault {
+			db.DB.Model(&models.CustomerAddress{}).Where("user_id = ?", userID).Update("is_default", false)
+		}
+
+		if err := db.DB.Create(&address).Error; err != nil {
+			log.Printf("Error creating address: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to save address",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    address,
+			"message": "Address saved successfully",
+		})
+	})
+
+	// Update a saved address
+	me.Put("/addresses/:id", func(c *fiber.Ctx) error {
+		userID := auth.Current(c).UserID
+		id := c.Params("id")
+
+		var address models.CustomerAddress
+		if err := db.DB.First(&address, "id = ? AND user_id = ?", id, userID).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Address not found",
+			})
+		}
+
+		var updateData models.CustomerAddress
+		if err := c.BodyParser(&updateData); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+		if err := validateCustomerAddress(&updateData); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("Validation error: %v", err),
+			})
+		}
+
+		if updateData.IsDefault {
+			db.DB.Model(&models.CustomerAddress{}).Where("user_id = ? AND id <> ?", userID, id).Update("is_default", false)
+		}
+
+		result := db.DB.Model(&address).Updates(map[string]interface{}{
+			"label":             updateData.Label,
+			"recipient_name":    updateData.RecipientName,
+			"recipient_phone":   updateData.RecipientPhone,
+			"address":           updateData.Address,
+			"delivery_location": updateData.DeliveryLocation,
+			"is_default":        updateData.IsDefault || address.IsDefault,
+		})
+		if result.Error != nil {
+			log.Printf("Error updating address: %v", result.Error)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to update address",
+			})
+		}
+		db.DB.First(&address, "id = ?", id)
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    address,
+			"message": "Address updated successfully",
+		})
+	})
+
+	// Delete a saved address
+	me.Delete("/addresses/:id", func(c *fiber.Ctx) error {
+		userID := auth.Current(c).UserID
+		id := c.Params("id")
+
+		var address models.CustomerAddress
+		if err := db.DB.First(&address, "id = ? AND user_id = ?", id, userID).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Address not found",
+			})
+		}
+
+		if err := db.DB.Delete(&address).Error; err != nil {
+			log.Printf("Error deleting address: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to delete address",
+			})
+		}
+
+		// Promote the oldest remaining address to default
+		if address.IsDefault {
+			var next models.CustomerAddress
+			if err := db.DB.Where("user_id = ?", userID).Order("created_at ASC").First(&next).Error; err == nil {
+				db.DB.Model(&next).Update("is_default", true)
+			}
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Address deleted successfully",
+		})
+	})
+}
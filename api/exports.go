@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/xlsx"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ==================== SPREADSHEET EXPORTS ====================
+
+// exportFilter narrows exported orders, dates are on the order creation date
+type exportFilter struct {
+	StartDate     string
+	EndDate       string
+	Statuses      []string
+	Location      string
+	PaymentMethod string
+	excluded      []string
+}
+
+// Read ?start_date&end_date&status&location&payment_method, the dates
+// default to the last 30 days like /api/reports. Without a status filter
+// excludedStatuses are left out.
+func parseExportFilter(c *fiber.Ctx, excludedStatuses []string) (exportFilter, string) {
+	f := exportFilter{
+		StartDate:     c.Query("start_date", time.Now().AddDate(0, 0, -30).Format("2006-01-02")),
+		EndDate:       c.Query("end_date", time.Now().Format("2006-01-02")),
+		Location:      strings.TrimSpace(c.Query("location")),
+		PaymentMethod: strings.TrimSpace(c.Query("payment_method")),
+	}
+	for _, date := range []string{f.StartDate, f.EndDate} {
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return f, "Dates must be in YYYY-MM-DD format"
+		}
+	}
+	for _, status := range strings.Split(c.Query("status"), ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			f.Statuses = append(f.Statuses, status)
+		}
+	}
+	if len(f.Statuses) == 0 {
+		f.Statuses = nil
+		f.excluded = excludedStatuses
+	}
+	return f, ""
+}
+
+// Apply the filter to a query on orders aliased as o
+func (f exportFilter) apply(q *gorm.DB) *gorm.DB {
+	q = q.Where("DATE(o.created_at) BETWEEN ? AND ?", f.StartDate, f.EndDate)
+	if len(f.Statuses) > 0 {
+		q = q.Where("o.order_status IN ?", f.Statuses)
+	} else if len(f.excluded) > 0 {
+		q = q.Where("o.order_status NOT IN ?", f.excluded)
+	}
+	if f.Location != "" {
+		q = q.Where("o.delivery_location = ?", f.Location)
+	}
+	if f.PaymentMethod != "" {
+		q = q.Where("LOWER(o.payment_method) = LOWER(?)", f.PaymentMethod)
+	}
+	return q
+}
+
+// exportSheet is one sheet of an export, Query selects its columns in
+// the order of Headers
+type exportSheet struct {
+	Key     string
+	Name    string
+	Headers []string
+	Query   func() *gorm.DB
+}
+
+// Stream the sheets as an XLSX workbook, or one of them as CSV picked with
+// ?sheet=, row by row straight from the database cursor
+func streamExport(c *fiber.Ctx, filename string, sheets []exportSheet) error {
+	format := c.Query("format", "csv")
+	if format != "csv" && format != "xlsx" {
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Format must be csv or xlsx",
+		})
+	}
+
+	if format == "csv" {
+		key := c.Query("sheet", sheets[0].Key)
+		var picked []exportSheet
+		keys := make([]string, len(sheets))
+		for i, sheet := range sheets {
+			keys[i] = sheet.Key
+			if sheet.Key == key {
+				picked = append(picked, sheet)
+			}
+		}
+		if len(picked) == 0 {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Sheet must be one of " + strings.Join(keys, ", "),
+			})
+		}
+		if len(sheets) > 1 {
+			filename += "-" + key
+		}
+		sheets = picked
+	}
+
+	if format == "xlsx" {
+		c.Set(fiber.HeaderContentType, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	} else {
+		c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	}
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		var err error
+		if format == "xlsx" {
+			err = writeXLSXExport(w, sheets)
+		} else {
+			err = writeCSVExport(w, sheets[0])
+		}
+		if err != nil {
+			// Headers are gone already, the download ends short
+			log.Printf("❌ Export %s failed: %v", filename, err)
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+// Run the sheet query and hand every row to fn, values converted to plain
+// Go types
+func eachExportRow(sheet exportSheet, fn func(values []interface{}) error) error {
+	rows, err := sheet.Query().Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return err
+		}
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = string(b)
+			}
+		}
+		if err := fn(values); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func writeCSVExport(w *bufio.Writer, sheet exportSheet) error {
+	w.WriteString("\ufeff") // Excel needs the BOM to read UTF-8
+	cw := csv.NewWriter(w)
+	if err := cw.Write(sheet.Headers); err != nil {
+		return err
+	}
+
+	record := make([]string, len(sheet.Headers))
+	count := 0
+	err := eachExportRow(sheet, func(values []interface{}) error {
+		for i := range record {
+			record[i] = ""
+			if i < len(values) {
+				record[i] = csvValue(values[i])
+			}
+		}
+		count++
+		if count%500 == 0 {
+			cw.Flush()
+		}
+		return cw.Write(record)
+	})
+	cw.Flush()
+	if err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func writeXLSXExport(w *bufio.Writer, sheets []exportSheet) error {
+	book := xlsx.NewWriter(w)
+	for _, sheet := range sheets {
+		out, err := book.NewSheet(sheet.Name, sheet.Headers...)
+		if err != nil {
+			return err
+		}
+		if err := eachExportRow(sheet, func(values []interface{}) error {
+			return out.WriteRow(values...)
+		}); err != nil {
+			return err
+		}
+	}
+	return book.Close()
+}
+
+// Format a database value for CSV, dates without a time of day as dates
+func csvValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	case time.Time:
+		if val.Hour() == 0 && val.Minute() == 0 && val.Second() == 0 && val.Nanosecond() == 0 {
+			return val.Format("2006-01-02")
+		}
+		return val.Format("2006-01-02 15:04:05")
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// Sheets of the financial report export
+func reportExportSheets(f exportFilter) []exportSheet {
+	return []exportSheet{
+		{
+			Key:  "orders",
+			Name: "Pesanan",
+			Headers: []string{"Tanggal", "No. Pesanan", "No. Invoice", "Status", "Metode Bayar", "Status Bayar",
+				"Lokasi", "Subtotal", "Ongkir", "Total", "HPP", "Laba Kotor"},
+			Query: func() *gorm.DB {
+				return f.apply(db.DB.Table("orders o").
+					Select(`DATE(o.created_at), o.order_number, COALESCE(o.invoice_number, ''), o.order_status,
+						o.payment_method, o.payment_status, o.delivery_location,
+						o.subtotal::float8, o.delivery_fee::float8, o.total::float8,
+						CASE WHEN o.order_status = 'completed' THEN o.cogs::float8 END,
+						CASE WHEN o.order_status = 'completed' THEN (o.total - o.cogs)::float8 END`)).
+					Order("o.created_at ASC, o.order_number ASC")
+			},
+		},
+		{
+			Key:  "items",
+			Name: "Item",
+			Headers: []string{"Tanggal", "No. Pesanan", "Status", "Produk", "Varian", "Kondisi", "Addon",
+				"Jumlah", "Harga", "Subtotal", "HPP", "Laba Kotor"},
+			Query: func() *gorm.DB {
+				return f.apply(db.DB.Table("order_items oi").
+					Select(`DATE(o.created_at), o.order_number, o.order_status, oi.product_name,
+						COALESCE(oi.variant_name, ''), COALESCE(oi.condition_name, ''), COALESCE(array_to_string(oi.addons, ', '), ''),
+						oi.quantity, oi.product_price::float8, oi.subtotal::float8,
+						CASE WHEN o.order_status = 'completed' THEN oi.cogs::float8 END,
+						CASE WHEN o.order_status = 'completed' THEN (oi.subtotal - oi.cogs)::float8 END`).
+					Joins("JOIN orders o ON o.id = oi.order_id")).
+					Order("o.created_at ASC, o.order_number ASC, oi.created_at ASC")
+			},
+		},
+		{
+			Key:     "daily",
+			Name:    "Harian",
+			Headers: []string{"Tanggal", "Pesanan", "Pendapatan", "Pendapatan Selesai", "HPP", "Laba Kotor"},
+			Query: func() *gorm.DB {
+				return f.apply(db.DB.Table("orders o").
+					Select(`DATE(o.created_at) AS date, COUNT(*), COALESCE(SUM(o.total), 0)::float8,
+						COALESCE(SUM(o.total) FILTER (WHERE o.order_status = 'completed'), 0)::float8,
+						COALESCE(SUM(o.cogs) FILTER (WHERE o.order_status = 'completed'), 0)::float8,
+						COALESCE(SUM(o.total - o.cogs) FILTER (WHERE o.order_status = 'completed'), 0)::float8`)).
+					Group("DATE(o.created_at)").
+					Order("date ASC")
+			},
+		},
+	}
+}
+
+// Sheets of the admin order export
+func orderExportSheets(f exportFilter) []exportSheet {
+	return []exportSheet{
+		{
+			Key:  "orders",
+			Name: "Pesanan",
+			Headers: []string{"No. Pesanan", "Dibuat", "Tanggal Kirim", "Status", "Metode Bayar", "Status Bayar",
+				"Lokasi", "Nama", "Telepon", "Email", "Alamat", "Catatan", "Jumlah Item",
+				"Subtotal", "Ongkir", "Total", "No. Invoice", "Kurir", "Dikirim"},
+			Query: func() *gorm.DB {
+				return f.apply(db.DB.Table("orders o").
+					Select(`o.order_number, o.created_at, o.delivery_date, o.order_status, o.payment_method, o.payment_status,
+						o.delivery_location, o.customer_name, o.customer_phone, o.customer_email,
+						COALESCE(o.delivery_address, ''), COALESCE(o.notes, ''),
+						(SELECT COALESCE(SUM(quantity), 0) FROM order_items WHERE order_id = o.id),
+						o.subtotal::float8, o.delivery_fee::float8, o.total::float8,
+						COALESCE(o.invoice_number, ''), COALESCE(u.name, ''), o.delivered_at`).
+					Joins("LEFT JOIN users u ON u.id = o.courier_id")).
+					Order("o.created_at ASC, o.order_number ASC")
+			},
+		},
+		{
+			Key:  "items",
+			Name: "Item",
+			Headers: []string{"No. Pesanan", "Dibuat", "Tanggal Kirim", "Status", "Lokasi", "Nama",
+				"Produk", "Varian", "Kondisi", "Addon", "Jumlah", "Harga", "Subtotal"},
+			Query: func() *gorm.DB {
+				return f.apply(db.DB.Table("order_items oi").
+					Select(`o.order_number, o.created_at, o.delivery_date, o.order_status, o.delivery_location, o.customer_name,
+						oi.product_name, COALESCE(oi.variant_name, ''), COALESCE(oi.condition_name, ''),
+						COALESCE(array_to_string(oi.addons, ', '), ''),
+						oi.quantity, oi.product_price::float8, oi.subtotal::float8`).
+					Joins("JOIN orders o ON o.id = oi.order_id")).
+					Order("o.created_at ASC, o.order_number ASC, oi.created_at ASC")
+			},
+		},
+	}
+}
+
+func registerExportRoutes(app *fiber.App) {
+	// Admin: Export financial report (?format=csv|xlsx&start_date&end_date&status&location&payment_method&sheet=orders|items|daily)
+	// The sheets list customers, so both exports need an admin session
+	app.Get("/api/reports/export", auth.RequireSession, auth.RequireAdmin, func(c *fiber.Ctx) error {
+		// Same exclusions as /api/reports
+		filter, msg := parseExportFilter(c, []string{"cancelled", "deleted"})
+		if msg != "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": msg,
+			})
+		}
+
+		log.Printf("📊 Report export: %s to %s", filter.StartDate, filter.EndDate)
+		return streamExport(c, fmt.Sprintf("laporan-%s-%s", filter.StartDate, filter.EndDate), reportExportSheets(filter))
+	})
+
+	// Admin: Export orders (?format=csv|xlsx&start_date&end_date&status&location&payment_method&sheet=orders|items)
+	app.Get("/api/admin/orders/export", auth.RequireSession, auth.RequireAdmin, func(c *fiber.Ctx) error {
+		filter, msg := parseExportFilter(c, nil)
+		if msg != "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": msg,
+			})
+		}
+
+		return streamExport(c, fmt.Sprintf("pesanan-%s-%s", filter.StartDate, filter.EndDate), orderExportSheets(filter))
+	})
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/email"
+	"scaff-food-backend/internal/uploads"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ==================== HEALTH PROBES ====================
+
+// Build information, set with
+// -ldflags "-X main.version=1.2.0 -X main.commit=$(git rev-parse --short HEAD)"
+var (
+	version = "dev"
+	commit  = ""
+)
+
+var startedAt = time.Now()
+
+// Every check of a probe runs within this, probes usually time out after 1s
+const probeTimeout = 800 * time.Millisecond
+
+// SMTP is a remote server, its result is reused for this long
+const smtpCheckTTL = 30 * time.Second
+
+// Result of one dependency check
+type probeCheck struct {
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+var smtpCheck struct {
+	mu        sync.Mutex
+	result    probeCheck
+	checkedAt time.Time
+}
+
+func buildCommit() string {
+	if commit != "" {
+		return commit
+	}
+	// Fall back to the revision go build stamps from the git checkout
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" && len(setting.Value) >= 7 {
+				return setting.Value[:7]
+			}
+		}
+	}
+	return "unknown"
+}
+
+func buildInfo() fiber.Map {
+	uptime := time.Since(startedAt)
+	return fiber.Map{
+		"version":        version,
+		"commit":         buildCommit(),
+		"started_at":     startedAt.Format(time.RFC3339),
+		"uptime":         uptime.Truncate(time.Second).String(),
+		"uptime_seconds": int64(uptime.Seconds()),
+	}
+}
+
+// Time a check and turn its error into a result
+func runCheck(check func() error) probeCheck {
+	start := time.Now()
+	err := check()
+	result := probeCheck{Status: "ok", LatencyMS: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// SMTP reachability, cached so frequent probes do not keep dialing out
+func checkSMTP(ctx context.Context) probeCheck {
+	config := getSMTPConfig()
+	if !config.Configured() {
+		return probeCheck{Status: "not_configured"}
+	}
+
+	smtpCheck.mu.Lock()
+	defer smtpCheck.mu.Unlock()
+	if time.Since(smtpCheck.checkedAt) < smtpCheckTTL {
+		return smtpCheck.result
+	}
+	smtpCheck.result = runCheck(func() error { return email.Ping(ctx, config) })
+	smtpCheck.checkedAt = time.Now()
+	return smtpCheck.result
+}
+
+// Register the health routes, they skip the database middleware
+func registerHealthRoutes(app *fiber.App) {
+	// Kept for existing monitors, only says the process answers
+	app.Get("/api/health", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"status": "ok",
+			"time":   time.Now().Format(time.RFC3339),
+		})
+	})
+
+	// Liveness: the process serves requests, dependencies are not checked
+	// so an outage does not get the container restarted
+	app.Get("/livez", func(c *fiber.Ctx) error {
+		info := buildInfo()
+		info["status"] = "ok"
+		return c.JSON(info)
+	})
+
+	// Readiness: Postgres answers and uploads can be stored. SMTP is only
+	// reported, the outbox retries email until it is back.
+	app.Get("/readyz", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(c.UserContext(), probeTimeout)
+		defer cancel()
+
+		var database, storage, smtp probeCheck
+		var wg sync.WaitGroup
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			database = runCheck(func() error { return db.Ping(ctx) })
+		}()
+		go func() {
+			defer wg.Done()
+			storage = runCheck(uploads.CheckWritable)
+		}()
+		go func() {
+			defer wg.Done()
+			smtp = checkSMTP(ctx)
+		}()
+		wg.Wait()
+
+		status, code := "ok", fiber.StatusOK
+		if database.Status != "ok" || storage.Status != "ok" {
+			status, code = "unavailable", fiber.StatusServiceUnavailable
+		}
+
+		info := buildInfo()
+		info["status"] = status
+		info["checks"] = fiber.Map{
+			"database": database,
+			"storage":  storage,
+			"smtp":     smtp,
+		}
+		return c.Status(code).JSON(info)
+	})
+}
@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"sync"
+	"time"
+
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/validate"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ==================== ADMIN OTP LOGIN ====================
+
+// Admin sessions last a working day
+const adminSessionTTL = 12 * time.Hour
+
+// Codes expire 5 minutes after they are sent
+const codeTTL = 5 * time.Minute
+
+type LoginRequest struct {
+	Email string `json:"email"`
+}
+
+type VerifyCodeRequest struct {
+	Email string `json:"email"`
+	Code  string `json:"code"`
+}
+
+type Response struct {
+	Success bool   `json:"success"`
+	Message string `json:"message"`
+	Token   string `json:"token,omitempty"`
+}
+
+// CodeSender delivers a verification code to an email address
+type CodeSender func(to, code string) error
+
+type CodeData struct {
+	Code      string
+	ExpiresAt time.Time
+}
+
+type CodeStore struct {
+	mu    sync.RWMutex
+	codes map[string]*CodeData
+}
+
+var codeStore = &CodeStore{
+	codes: make(map[string]*CodeData),
+}
+
+func (s *CodeStore) put(key, code string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.codes[key] = &CodeData{
+		Code:      code,
+		ExpiresAt: time.Now().Add(codeTTL),
+	}
+}
+
+func (s *CodeStore) get(key string) (*CodeData, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	codeData, exists := s.codes[key]
+	return codeData, exists
+}
+
+func (s *CodeStore) remove(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.codes, key)
+}
+
+func generateCode() string {
+	return fmt.Sprintf("%06d", rand.Intn(1000000))
+}
+
+// Only existing non-customer users may log in as admin
+func isEmailAllowed(email string) bool {
+	if !db.Ready() {
+		log.Println("❌ Database not connected, rejecting login")
+		return false
+	}
+
+	var user models.User
+	result := db.DB.Where("email = ?", email).First(&user)
+
+	if result.Error != nil {
+		log.Printf("❌ Email %s not found in database", email)
+		return false
+	}
+
+	// Customers have their own OTP flow and must not get admin sessions
+	if user.Role == RoleCustomer {
+		log.Printf("❌ Email %s is a customer account, rejecting admin login", email)
+		return false
+	}
+
+	log.Printf("✅ Email %s found in database (role: %s)", email, user.Role)
+	return true
+}
+
+// RegisterRoutes mounts the admin and customer OTP logins. sendCode delivers
+// the verification codes.
+func RegisterRoutes(app fiber.Router, sendCode CodeSender) {
+	app.Post("/api/auth/send-code", func(c *fiber.Ctx) error {
+		var req LoginRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(Response{
+				Success: false,
+				Message: "Invalid request",
+			})
+		}
+		req.Email = validate.NormalizeEmail(req.Email)
+
+		if req.Email == "" {
+			return c.Status(400).JSON(Response{
+				Success: false,
+				Message: "Email is required",
+			})
+		}
+
+		// Check if email is allowed (exists in database)
+		if !isEmailAllowed(req.Email) {
+			return c.Status(401).JSON(Response{
+				Success: false,
+				Message: "Email atau kode verifikasi salah.",
+			})
+		}
+
+		// Generate 6-digit code
+		code := generateCode()
+		codeStore.put(req.Email, code)
+
+		// Queue email, the outbox sends it in the background
+		if err := sendCode(req.Email, code); err != nil {
+			log.Printf("ERROR: Failed to queue email to %s: %v", req.Email, err)
+			log.Printf("Code for %s: %s (email failed, showing in logs)", req.Email, code)
+		} else {
+			log.Printf("SUCCESS: Verification code queued for %s", req.Email)
+		}
+
+		return c.JSON(Response{
+			Success: true,
+			Message: fmt.Sprintf("Kode verifikasi telah dikirim ke %s", req.Email),
+		})
+	})
+
+	app.Post("/api/auth/verify-code", func(c *fiber.Ctx) error {
+		var req VerifyCodeRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(Response{
+				Success: false,
+				Message: "Invalid request",
+			})
+		}
+		req.Email = validate.NormalizeEmail(req.Email)
+
+		if req.Email == "" || req.Code == "" {
+			return c.Status(400).JSON(Response{
+				Success: false,
+				Message: "Email and code are required",
+			})
+		}
+
+		codeData, exists := codeStore.get(req.Email)
+		if !exists {
+			return c.Status(401).JSON(Response{
+				Success: false,
+				Message: "Email atau kode verifikasi salah.",
+			})
+		}
+
+		// Check if code expired
+		if time.Now().After(codeData.ExpiresAt) {
+			codeStore.remove(req.Email)
+			return c.Status(401).JSON(Response{
+				Success: false,
+				Message: "Kode verifikasi telah kadaluarsa. Silakan minta kode baru.",
+			})
+		}
+
+		// Check if code matches
+		if codeData.Code != req.Code {
+			return c.Status(401).JSON(Response{
+				Success: false,
+				Message: "Email atau kode verifikasi salah.",
+			})
+		}
+
+		// Open session for the admin user
+		var user models.User
+		if db.DB.Where("email = ?", req.Email).First(&user).Error != nil {
+			return c.Status(401).JSON(Response{
+				Success: false,
+				Message: "Email atau kode verifikasi salah.",
+			})
+		}
+		token := Sessions.Create(user.ID, user.Email, user.Role, adminSessionTTL)
+
+		// Remove used code
+		codeStore.remove(req.Email)
+
+		return c.JSON(Response{
+			Success: true,
+			Message: "Login successful",
+			Token:   token,
+		})
+	})
+
+	registerCustomerRoutes(app, sendCode)
+}
@@ -0,0 +1,144 @@
+package auth
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/security"
+	"scaff-food-backend/internal/validate"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ==================== CUSTOMER OTP LOGIN ====================
+
+// Customer sessions last longer than the OTP itself
+const customerSessionTTL = 24 * time.Hour
+
+// CustomerVerifyRequest verifies the OTP and optionally fills in the profile
+// used when the account is created on first login
+type CustomerVerifyRequest struct {
+	Email string `json:"email"`
+	Code  string `json:"code"`
+	Name  string `json:"name"`
+	Phone string `json:"phone"`
+}
+
+// Customer codes share codeStore with admin codes under a separate key
+func customerCodeKey(email string) string {
+	return "customer:" + email
+}
+
+func registerCustomerRoutes(app fiber.Router, sendCode CodeSender) {
+	// Send OTP to any customer email (no users row required)
+	app.Post("/api/auth/customer/send-code", func(c *fiber.Ctx) error {
+		var req LoginRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(Response{
+				Success: false,
+				Message: "Invalid request",
+			})
+		}
+
+		email := validate.NormalizeEmail(req.Email)
+		if !validate.IsValidEmail(email) {
+			return c.Status(400).JSON(Response{
+				Success: false,
+				Message: "Email tidak valid",
+			})
+		}
+
+		code := generateCode()
+		codeStore.put(customerCodeKey(email), code)
+
+		if err := sendCode(email, code); err != nil {
+			log.Printf("ERROR: Failed to queue customer code to %s: %v", email, err)
+			log.Printf("Customer code for %s: %s (email failed, showing in logs)", email, code)
+		}
+
+		return c.JSON(Response{
+			Success: true,
+			Message: fmt.Sprintf("Kode verifikasi telah dikirim ke %s", email),
+		})
+	})
+
+	// Verify customer OTP, register the account on first login and open a session
+	app.Post("/api/auth/customer/verify-code", func(c *fiber.Ctx) error {
+		var req CustomerVerifyRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(Response{
+				Success: false,
+				Message: "Invalid request",
+			})
+		}
+
+		email := validate.NormalizeEmail(req.Email)
+		if email == "" || req.Code == "" {
+			return c.Status(400).JSON(Response{
+				Success: false,
+				Message: "Email and code are required",
+			})
+		}
+
+		key := customerCodeKey(email)
+		codeData, exists := codeStore.get(key)
+		if !exists || time.Now().After(codeData.ExpiresAt) || codeData.Code != req.Code {
+			security.Blacklist.TrackAttempt(c.IP())
+			return c.Status(401).JSON(Response{
+				Success: false,
+				Message: "Email atau kode verifikasi salah.",
+			})
+		}
+		codeStore.remove(key)
+
+		user, err := findOrCreateCustomer(email, req.Name, req.Phone)
+		if err != nil {
+			log.Printf("Error registering customer %s: %v", email, err)
+			return c.Status(500).JSON(Response{
+				Success: false,
+				Message: "Failed to open customer account",
+			})
+		}
+
+		token := Sessions.Create(user.ID, email, RoleCustomer, customerSessionTTL)
+		log.Printf("✅ Customer session opened for %s", email)
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Verifikasi berhasil",
+			"token":   token,
+			"user":    user,
+		})
+	})
+}
+
+// Find the users row for a verified email, creating a customer account if missing
+func findOrCreateCustomer(email, name, phone string) (*models.User, error) {
+	var user models.User
+	err := db.DB.Where("email = ?", email).First(&user).Error
+	if err == nil {
+		return &user, nil
+	}
+	if err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+
+	user = models.User{
+		Email: email,
+		Name:  validate.SanitizeString(name),
+		Role:  RoleCustomer,
+	}
+	if normalized, ok := validate.NormalizePhone(phone); ok {
+		user.Phone = normalized
+	}
+	if err := db.DB.Create(&user).Error; err != nil {
+		return nil, err
+	}
+
+	log.Printf("✅ Customer account registered: %s", email)
+	return &user, nil
+}
@@ -0,0 +1,173 @@
+// Package auth handles the OTP logins of admins and customers and the
+// sessions they open.
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ==================== SESSIONS ====================
+
+// User roles sessions are checked against
+const (
+	// RoleAdmin is allowed into the admin routes
+	RoleAdmin = "admin"
+	// RoleCustomer logs in with the customer OTP flow and uses /api/me
+	RoleCustomer = "customer"
+	// RoleCourier logs in with the admin OTP flow and uses /api/courier/me
+	RoleCourier = "courier"
+)
+
+// Session is what a verified OTP login turns into
+type Session struct {
+	UserID    string
+	Email     string
+	Role      string
+	ExpiresAt time.Time
+}
+
+type SessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]*Session
+}
+
+// Sessions holds every open session in memory
+var Sessions = &SessionStore{
+	sessions: make(map[string]*Session),
+}
+
+// StreamTokenTTL is how long a stream token can be used to open a stream
+const StreamTokenTTL = time.Minute
+
+// StreamTokens are short-lived tokens for EventSource streams, which cannot
+// send an Authorization header. Only StreamSession accepts them.
+var StreamTokens = &SessionStore{
+	sessions: make(map[string]*Session),
+}
+
+// GenerateToken returns a cryptographically random hex token of n bytes
+func GenerateToken(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// Create a new session and return its token
+func (s *SessionStore) Create(userID, email, role string, ttl time.Duration) string {
+	token := GenerateToken(32)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions[token] = &Session{
+		UserID:    userID,
+		Email:     email,
+		Role:      role,
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	return token
+}
+
+// Get a session by token, expired sessions are treated as missing
+func (s *SessionStore) Get(token string) (*Session, bool) {
+	s.mu.RLock()
+	session, exists := s.sessions[token]
+	s.mu.RUnlock()
+
+	if !exists {
+		return nil, false
+	}
+	if time.Now().After(session.ExpiresAt) {
+		s.mu.Lock()
+		delete(s.sessions, token)
+		s.mu.Unlock()
+		return nil, false
+	}
+	return session, true
+}
+
+// CleanExpired sessions, called periodically
+func (s *SessionStore) CleanExpired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for token, session := range s.sessions {
+		if now.After(session.ExpiresAt) {
+			delete(s.sessions, token)
+		}
+	}
+}
+
+// BearerToken reads the bearer token from the Authorization header
+func BearerToken(c *fiber.Ctx) string {
+	header := c.Get("Authorization")
+	if strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+	}
+	return ""
+}
+
+// RequireSession is a middleware requiring a valid session, stored in
+// c.Locals("session")
+func RequireSession(c *fiber.Ctx) error {
+	// Already attached, e.g. by StreamSession
+	if Current(c) != nil {
+		return c.Next()
+	}
+	session, ok := Sessions.Get(BearerToken(c))
+	if !ok {
+		return c.Status(401).JSON(fiber.Map{
+			"success": false,
+			"message": "Sesi tidak valid. Silakan verifikasi ulang.",
+		})
+	}
+	c.Locals("session", session)
+	return c.Next()
+}
+
+// RequireAdmin is a middleware, after RequireSession, letting only admin
+// sessions through
+func RequireAdmin(c *fiber.Ctx) error {
+	session := Current(c)
+	if session == nil || session.Role != RoleAdmin {
+		return c.Status(403).JSON(fiber.Map{
+			"success": false,
+			"message": "Hanya admin yang dapat mengakses halaman ini.",
+		})
+	}
+	return c.Next()
+}
+
+// OptionalSession is a middleware attaching the session if a valid token is
+// sent, but never rejecting
+func OptionalSession(c *fiber.Ctx) error {
+	if session, ok := Sessions.Get(BearerToken(c)); ok {
+		c.Locals("session", session)
+	}
+	return c.Next()
+}
+
+// StreamSession is a middleware for SSE routes attaching the session of a
+// valid ?stream_token= (see StreamTokens), RequireSession then lets it through
+func StreamSession(c *fiber.Ctx) error {
+	if c.Method() == fiber.MethodGet {
+		if session, ok := StreamTokens.Get(c.Query("stream_token")); ok {
+			c.Locals("session", session)
+		}
+	}
+	return c.Next()
+}
+
+// Current returns the session stored by RequireSession or OptionalSession
+func Current(c *fiber.Ctx) *Session {
+	session, _ := c.Locals("session").(*Session)
+	return session
+}
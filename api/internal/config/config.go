@@ -0,0 +1,379 @@
+// Package config loads the service configuration from an optional YAML file
+// and environment variables, and validates it before anything starts.
+//
+// Values are applied in order: built-in development defaults, the YAML file
+// (CONFIG_FILE, or config.yaml when present), then environment variables.
+// Environment variables always win, so a deploy can override single values
+// without editing the file.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Environments the service knows
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// Default file read when CONFIG_FILE is not set, ignored when missing
+const defaultFile = "config.yaml"
+
+// Passwords that ship in examples and compose files, never valid in production
+var insecurePasswords = []string{"", "change_me", "docker123", "postgres", "password"}
+
+type Config struct {
+	// Env is development or production, production refuses insecure defaults
+	Env  string `yaml:"env"`
+	Port string `yaml:"port"`
+	// PublicURL is where customers reach this API, used in tracking links
+	PublicURL     string         `yaml:"public_url"`
+	Database      DatabaseConfig `yaml:"database"`
+	SMTP          SMTPConfig     `yaml:"smtp"`
+	CORS          CORSConfig     `yaml:"cors"`
+	WhatsApp      WhatsAppConfig `yaml:"whatsapp"`
+	Outbox        OutboxConfig   `yaml:"outbox"`
+	MigrationsDir string         `yaml:"migrations_dir"`
+	// StockAlertEmails receive stock alerts, every admin user when empty
+	StockAlertEmails []string `yaml:"stock_alert_emails"`
+}
+
+// DatabaseConfig is either a full URL (Render, Heroku, etc) or the separate
+// connection values used for local development
+type DatabaseConfig struct {
+	URL      string `yaml:"url"`
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+type CORSConfig struct {
+	AllowedOrigins []string `yaml:"allowed_origins"`
+}
+
+// WhatsAppConfig is optional, notifications are only logged without it.
+// Empty API values fall back to the notification package defaults.
+type WhatsAppConfig struct {
+	AccessToken      string `yaml:"access_token"`
+	PhoneNumberID    string `yaml:"phone_number_id"`
+	APIURL           string `yaml:"api_url"`
+	APIVersion       string `yaml:"api_version"`
+	TemplateLanguage string `yaml:"template_language"`
+}
+
+type OutboxConfig struct {
+	Workers     int `yaml:"workers"`
+	MaxAttempts int `yaml:"max_attempts"`
+}
+
+// Defaults are meant for local development only, Validate rejects the
+// insecure ones in production
+func Defaults() *Config {
+	return &Config{
+		Env:       EnvDevelopment,
+		Port:      "8080",
+		PublicURL: "http://localhost:8080",
+		Database: DatabaseConfig{
+			Host:     "localhost",
+			Port:     "5432",
+			User:     "postgres",
+			Password: "change_me",
+			Name:     "management_preorder",
+		},
+		SMTP: SMTPConfig{
+			Host: "smtp.gmail.com",
+			Port: "587",
+			From: "noreply@scafffood.com",
+		},
+		CORS:          CORSConfig{AllowedOrigins: []string{"*"}},
+		Outbox:        OutboxConfig{Workers: 4, MaxAttempts: 5},
+		MigrationsDir: "migrations",
+	}
+}
+
+// Load reads the YAML file and the environment on top of the defaults, then
+// validates the result
+func Load() (*Config, error) {
+	cfg := Defaults()
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		if _, err := os.Stat(defaultFile); err == nil {
+			path = defaultFile
+		}
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(os.Getenv); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer file.Close()
+
+	// Unknown keys are most likely typos, refuse them
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// Apply the environment variables that are set
+func (c *Config) loadEnv(getenv func(string) string) error {
+	text := map[string]*string{
+		"APP_ENV":                    &c.Env,
+		"PORT":                       &c.Port,
+		"PUBLIC_URL":                 &c.PublicURL,
+		"DATABASE_URL":               &c.Database.URL,
+		"DB_HOST":                    &c.Database.Host,
+		"DB_PORT":                    &c.Database.Port,
+		"DB_USER":                    &c.Database.User,
+		"DB_PASSWORD":                &c.Database.Password,
+		"DB_NAME":                    &c.Database.Name,
+		"SMTP_HOST":                  &c.SMTP.Host,
+		"SMTP_PORT":                  &c.SMTP.Port,
+		"SMTP_USERNAME":              &c.SMTP.Username,
+		"SMTP_PASSWORD":              &c.SMTP.Password,
+		"SMTP_FROM":                  &c.SMTP.From,
+		"WHATSAPP_ACCESS_TOKEN":      &c.WhatsApp.AccessToken,
+		"WHATSAPP_PHONE_NUMBER_ID":   &c.WhatsApp.PhoneNumberID,
+		"WHATSAPP_API_URL":           &c.WhatsApp.APIURL,
+		"WHATSAPP_API_VERSION":       &c.WhatsApp.APIVersion,
+		"WHATSAPP_TEMPLATE_LANGUAGE": &c.WhatsApp.TemplateLanguage,
+		"MIGRATIONS_DIR":             &c.MigrationsDir,
+	}
+	for key, field := range text {
+		if value := getenv(key); value != "" {
+			*field = value
+		}
+	}
+
+	lists := map[string]*[]string{
+		"CORS_ALLOWED_ORIGINS": &c.CORS.AllowedOrigins,
+		"STOCK_ALERT_EMAILS":   &c.StockAlertEmails,
+	}
+	for key, field := range lists {
+		if value := getenv(key); value != "" {
+			*field = splitList(value)
+		}
+	}
+
+	var errs []error
+	ints := map[string]*int{
+		"OUTBOX_WORKERS":      &c.Outbox.Workers,
+		"OUTBOX_MAX_ATTEMPTS": &c.Outbox.MaxAttempts,
+	}
+	for key, field := range ints {
+		value := getenv(key)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s must be a number, got %q", key, value))
+			continue
+		}
+		*field = n
+	}
+	return errors.Join(errs...)
+}
+
+// Split a comma separated list, dropping blanks
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// IsProduction reports whether the service runs in production mode
+func (c *Config) IsProduction() bool {
+	return c.Env == EnvProduction
+}
+
+// Validate checks required values and, in production, refuses the insecure
+// development defaults. Every problem is reported at once.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		fail("APP_ENV must be %s or %s, got %q", EnvDevelopment, EnvProduction, c.Env)
+	}
+	if !isPort(c.Port) {
+		fail("PORT must be a port number, got %q", c.Port)
+	}
+	if u, err := url.Parse(c.PublicURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fail("PUBLIC_URL must be an absolute http or https URL, got %q", c.PublicURL)
+	}
+
+	if c.Database.URL != "" {
+		if _, err := url.Parse(c.Database.URL); err != nil {
+			fail("DATABASE_URL is not a valid URL")
+		}
+	} else {
+		if c.Database.Host == "" || c.Database.User == "" || c.Database.Name == "" {
+			fail("DB_HOST, DB_USER and DB_NAME are required without DATABASE_URL")
+		}
+		if !isPort(c.Database.Port) {
+			fail("DB_PORT must be a port number, got %q", c.Database.Port)
+		}
+	}
+
+	if !isPort(c.SMTP.Port) {
+		fail("SMTP_PORT must be a port number, got %q", c.SMTP.Port)
+	}
+	if (c.SMTP.Username == "") != (c.SMTP.Password == "") {
+		fail("SMTP_USERNAME and SMTP_PASSWORD must be set together")
+	}
+
+	if len(c.CORS.AllowedOrigins) == 0 {
+		fail("CORS_ALLOWED_ORIGINS must list at least one origin")
+	}
+	if (c.WhatsApp.AccessToken == "") != (c.WhatsApp.PhoneNumberID == "") {
+		fail("WHATSAPP_ACCESS_TOKEN and WHATSAPP_PHONE_NUMBER_ID must be set together")
+	}
+	if c.Outbox.Workers < 1 {
+		fail("OUTBOX_WORKERS must be at least 1")
+	}
+	if c.Outbox.MaxAttempts < 1 {
+		fail("OUTBOX_MAX_ATTEMPTS must be at least 1")
+	}
+	if c.MigrationsDir == "" {
+		fail("MIGRATIONS_DIR is required")
+	}
+
+	if c.IsProduction() {
+		if isInsecurePassword(c.Database.databasePassword()) {
+			fail("database password must be set to a non-default value in production")
+		}
+		for _, origin := range c.CORS.AllowedOrigins {
+			if origin == "*" {
+				fail("CORS_ALLOWED_ORIGINS must list the frontend origins in production, not *")
+			}
+		}
+		if c.SMTP.Username == "" {
+			fail("SMTP credentials are required in production, login codes would only be logged")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func isPort(value string) bool {
+	n, err := strconv.Atoi(value)
+	return err == nil && n > 0 && n < 65536
+}
+
+func isInsecurePassword(password string) bool {
+	for _, insecure := range insecurePasswords {
+		if password == insecure {
+			return true
+		}
+	}
+	return false
+}
+
+// The password actually used to connect, from the URL when one is set
+func (d DatabaseConfig) databasePassword() string {
+	if d.URL == "" {
+		return d.Password
+	}
+	u, err := url.Parse(d.URL)
+	if err != nil || u.User == nil {
+		return ""
+	}
+	password, _ := u.User.Password()
+	return password
+}
+
+// DSN is the Postgres connection string
+func (d DatabaseConfig) DSN() string {
+	if d.URL != "" {
+		return d.URL
+	}
+	return fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
+		d.Host, d.User, d.Password, d.Name, d.Port,
+	)
+}
+
+// Mask a secret, only telling whether it is set
+func redact(secret string) string {
+	if secret == "" {
+		return "(not set)"
+	}
+	return "****"
+}
+
+// Summary describes the configuration with every secret redacted, one line
+// per section, for the boot log
+func (c *Config) Summary() []string {
+	database := fmt.Sprintf("%s@%s:%s/%s password=%s",
+		c.Database.User, c.Database.Host, c.Database.Port, c.Database.Name, redact(c.Database.Password))
+	if c.Database.URL != "" {
+		database = "DATABASE_URL (unparseable)"
+		if u, err := url.Parse(c.Database.URL); err == nil {
+			database = u.Redacted()
+		}
+	}
+
+	whatsApp := "disabled"
+	if c.WhatsApp.PhoneNumberID != "" {
+		whatsApp = fmt.Sprintf("phone_number_id=%s access_token=%s", c.WhatsApp.PhoneNumberID, redact(c.WhatsApp.AccessToken))
+	}
+
+	stockAlerts := "every admin user"
+	if len(c.StockAlertEmails) > 0 {
+		stockAlerts = strings.Join(c.StockAlertEmails, ", ")
+	}
+
+	return []string{
+		fmt.Sprintf("env: %s, port: %s, public url: %s", c.Env, c.Port, c.PublicURL),
+		fmt.Sprintf("database: %s", database),
+		fmt.Sprintf("smtp: %s:%s username=%s password=%s from=%s",
+			c.SMTP.Host, c.SMTP.Port, c.SMTP.Username, redact(c.SMTP.Password), c.SMTP.From),
+		fmt.Sprintf("cors: %s", strings.Join(c.CORS.AllowedOrigins, ", ")),
+		fmt.Sprintf("whatsapp: %s", whatsApp),
+		fmt.Sprintf("outbox: workers=%d max_attempts=%d", c.Outbox.Workers, c.Outbox.MaxAttempts),
+		fmt.Sprintf("stock alerts: %s", stockAlerts),
+	}
+}
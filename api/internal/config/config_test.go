@@ -0,0 +1,118 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func env(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestDefaultsAreValidInDevelopment(t *testing.T) {
+	if err := Defaults().Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+}
+
+func TestEnvOverridesDefaults(t *testing.T) {
+	cfg := Defaults()
+	err := cfg.loadEnv(env(map[string]string{
+		"PORT":                 "9090",
+		"DB_HOST":              "db",
+		"CORS_ALLOWED_ORIGINS": "https://scafffood.com, https://admin.scafffood.com",
+		"STOCK_ALERT_EMAILS":   "owner@example.com,,kitchen@example.com",
+		"OUTBOX_WORKERS":       "8",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Port != "9090" || cfg.Database.Host != "db" || cfg.Database.User != "postgres" {
+		t.Errorf("config = %+v", cfg)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 2 || cfg.CORS.AllowedOrigins[1] != "https://admin.scafffood.com" {
+		t.Errorf("origins = %q", cfg.CORS.AllowedOrigins)
+	}
+	if len(cfg.StockAlertEmails) != 2 {
+		t.Errorf("stock alert emails = %q", cfg.StockAlertEmails)
+	}
+	if cfg.Outbox.Workers != 8 || cfg.Outbox.MaxAttempts != 5 {
+		t.Errorf("outbox = %+v", cfg.Outbox)
+	}
+}
+
+func TestEnvRejectsBadNumbers(t *testing.T) {
+	if err := Defaults().loadEnv(env(map[string]string{"OUTBOX_WORKERS": "many"})); err == nil {
+		t.Error("loadEnv accepted OUTBOX_WORKERS=many")
+	}
+}
+
+func TestProductionRefusesInsecureDefaults(t *testing.T) {
+	cfg := Defaults()
+	cfg.Env = EnvProduction
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("Validate() accepted development defaults in production")
+	}
+	for _, want := range []string{"database password", "CORS_ALLOWED_ORIGINS", "SMTP credentials"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %s", err, want)
+		}
+	}
+
+	cfg.Database.Password = "s3cret-and-long"
+	cfg.CORS.AllowedOrigins = []string{"https://scafffood.com"}
+	cfg.SMTP.Username, cfg.SMTP.Password = "mailer", "app-password"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	// The password inside DATABASE_URL is checked as well
+	cfg.Database.URL = "postgres://postgres:postgres@db:5432/app"
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() accepted DATABASE_URL with the postgres password")
+	}
+}
+
+func TestLoadFileRejectsUnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.yaml")
+	bad := filepath.Join(dir, "bad.yaml")
+	os.WriteFile(good, []byte("port: \"3000\"\ndatabase:\n  host: db\ncors:\n  allowed_origins: [https://scafffood.com]\n"), 0o644)
+	os.WriteFile(bad, []byte("database:\n  hostname: db\n"), 0o644)
+
+	cfg := Defaults()
+	if err := cfg.loadFile(good); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != "3000" || cfg.Database.Host != "db" || cfg.Database.Name != "management_preorder" {
+		t.Errorf("config = %+v", cfg)
+	}
+
+	if err := Defaults().loadFile(bad); err == nil {
+		t.Error("loadFile accepted an unknown key")
+	}
+}
+
+func TestSummaryRedactsSecrets(t *testing.T) {
+	cfg := Defaults()
+	cfg.Database.Password = "db-secret"
+	cfg.SMTP.Password = "smtp-secret"
+	cfg.WhatsApp.AccessToken = "wa-secret"
+	summary := strings.Join(cfg.Summary(), "\n")
+
+	for _, secret := range []string{"db-secret", "smtp-secret", "wa-secret"} {
+		if strings.Contains(summary, secret) {
+			t.Errorf("summary leaks %s:\n%s", secret, summary)
+		}
+	}
+
+	cfg.Database.URL = "postgres://app:url-secret@db:5432/app"
+	if summary := strings.Join(cfg.Summary(), "\n"); strings.Contains(summary, "url-secret") {
+		t.Errorf("summary leaks the DATABASE_URL password:\n%s", summary)
+	}
+}
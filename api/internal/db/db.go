@@ -1,86 +1,142 @@
+// Package db holds the Postgres connection shared by the whole service and
+// keeps it usable while the database goes down and comes back.
 package db
 
 import (
-	"fmt"
+	"context"
+	"errors"
 	"log"
-	"os"
+	"sync/atomic"
 	"time"
 
+	"scaff-food-backend/internal/config"
+
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// Health check and reconnect timing
+const (
+	checkInterval = 10 * time.Second
+	pingTimeout   = 3 * time.Second
+	minBackoff    = time.Second
+	maxBackoff    = 30 * time.Second
+)
+
+// DB is the connection pool, opened by Connect and never replaced. It may
+// point at an unreachable database, check Ready before using it.
 var DB *gorm.DB
 
-func ConnectDb() {
-	var dsn string
-	
-	// Check if DATABASE_URL is provided (Render, Heroku, etc)
-	databaseURL := os.Getenv("DATABASE_URL")
-	
-	if databaseURL != "" {
-		// Use DATABASE_URL from cloud provider
+var ready atomic.Bool
+
+// Ready reports whether the database answered the last health check
+func Ready() bool {
+	return DB != nil && ready.Load()
+}
+
+// Get returns DB, or nil while the database is unreachable. Pass it instead
+// of DB itself to code built before the connection is opened.
+func Get() *gorm.DB {
+	if !Ready() {
+		return nil
+	}
+	return DB
+}
+
+// Connect opens the pool and checks the database once. When it is not
+// reachable the service still starts and Watch keeps retrying.
+func Connect(cfg config.DatabaseConfig) {
+	if cfg.URL != "" {
 		log.Println("🌐 Using DATABASE_URL from environment")
-		dsn = databaseURL
 	} else {
-		// Use individual environment variables (local development)
-		host := getEnv("DB_HOST", "postgres") 
-		port := getEnv("DB_PORT", "5432")
-		user := getEnv("DB_USER", "postgres")
-		password := getEnv("DB_PASSWORD", "docker123")
-		dbname := getEnv("DB_NAME", "management_preorder")
-		
-		log.Printf("🐳 LOCAL: Connecting to %s@%s:%s/%s", user, host, port, dbname)
-		
-		dsn = fmt.Sprintf(
-			"host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-			host, user, password, dbname, port,
-		)
-	}
-	
-	// Retry connection
-	var db *gorm.DB
-	var err error
-	maxRetries := 25
-	
-	for i := 1; i <= maxRetries; i++ {
-		db, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
-		if err != nil {
-			log.Printf("⏳ Database not ready (attempt %d/%d)...", i, maxRetries)
-			time.Sleep(3 * time.Second) 
-			continue
-		}
-		break
+		log.Printf("🔌 Connecting to database: %s@%s:%s/%s", cfg.User, cfg.Host, cfg.Port, cfg.Name)
 	}
-	
+
+	// The pool dials lazily, so opening it only fails on a malformed DSN
+	conn, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{DisableAutomaticPing: true})
 	if err != nil {
-		log.Fatal("❌ Failed to connect to database:", err)
+		log.Printf("❌ Failed to open database: %v", err)
+		return
 	}
-	
-	DB = db
-	
-	// Test connection
-	sqlDB, err := db.DB()
+	sqlDB, err := conn.DB()
 	if err != nil {
-		log.Fatal("❌ Failed to get DB instance:", err)
-	}
-	
-	if err := sqlDB.Ping(); err != nil {
-		log.Fatal("❌ Database ping failed:", err)
+		log.Printf("❌ Failed to get DB instance: %v", err)
+		return
 	}
-	
+
 	// Configure connection pool
 	sqlDB.SetMaxIdleConns(5)
 	sqlDB.SetMaxOpenConns(20)
 	sqlDB.SetConnMaxLifetime(30 * time.Minute)
-	
-	log.Println("✅ Connected to PostgreSQL successfully!")
+	DB = conn
+
+	if err := Ping(context.Background()); err != nil {
+		log.Printf("❌ Failed to connect to database: %v", err)
+		log.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		log.Println("⚠️  DATABASE NOT CONNECTED!")
+		log.Println("⚠️  API requests get 503 until the database is reachable")
+		log.Println("⚠️  Start database with: ./start-db.sh")
+		log.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+		return
+	}
+
+	ready.Store(true)
+	log.Println("✅ Connected to PostgreSQL database!")
+	log.Println("✅ Login system is ready!")
 }
 
-func getEnv(key, defaultValue string) string {
-	value := os.Getenv(key)
-	if value == "" {
-		return defaultValue
+// Watch checks the database in the background until ctx is done. While it
+// is down, reconnects are retried with exponential backoff. onReconnect runs
+// each time it comes back, before requests are let through again.
+func Watch(ctx context.Context, onReconnect func()) {
+	if DB == nil {
+		return
 	}
-	return value
-}
\ No newline at end of file
+
+	go func() {
+		backoff := minBackoff
+		for {
+			wait := checkInterval
+			if !ready.Load() {
+				wait = backoff
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+
+			err := Ping(ctx)
+			switch {
+			case err != nil && ready.Load():
+				ready.Store(false)
+				backoff = minBackoff
+				log.Printf("⚠️  Database connection lost, API requests get 503: %v", err)
+			case err != nil:
+				backoff = min(backoff*2, maxBackoff)
+				log.Printf("⚠️  Database still unreachable, retrying in %v: %v", backoff, err)
+			case !ready.Load():
+				if onReconnect != nil {
+					onReconnect()
+				}
+				ready.Store(true)
+				log.Println("✅ Reconnected to PostgreSQL database!")
+			}
+		}
+	}()
+}
+
+// Ping checks that the database answers, giving up after pingTimeout
+func Ping(ctx context.Context) error {
+	if DB == nil {
+		return errors.New("database not configured")
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	return sqlDB.PingContext(ctx)
+}
@@ -0,0 +1,182 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"time"
+)
+
+// Config holds SMTP settings
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Configured reports whether SMTP credentials are set
+func (c *Config) Configured() bool {
+	return c.Username != "" && c.Password != ""
+}
+
+// ImplicitTLS reports whether the server expects TLS from the first byte
+// (SMTPS on port 465) instead of upgrading with STARTTLS
+func (c *Config) ImplicitTLS() bool {
+	return c.Port == "465"
+}
+
+// BuildMessage composes a multipart/alternative message with text and HTML parts
+func BuildMessage(from, to string, r *Rendered) ([]byte, error) {
+	boundaryBytes := make([]byte, 12)
+	if _, err := rand.Read(boundaryBytes); err != nil {
+		return nil, err
+	}
+	boundary := "scafffood-" + hex.EncodeToString(boundaryBytes)
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", r.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n", boundary)
+	msg.WriteString("\r\n")
+
+	// Plain text first, clients show the last part they support
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=UTF-8", r.Text},
+		{"text/html; charset=UTF-8", r.HTML},
+	}
+	for _, part := range parts {
+		fmt.Fprintf(&msg, "--%s\r\n", boundary)
+		fmt.Fprintf(&msg, "Content-Type: %s\r\n", part.contentType)
+		msg.WriteString("Content-Transfer-Encoding: quoted-printable\r\n")
+		msg.WriteString("\r\n")
+
+		qp := quotedprintable.NewWriter(&msg)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+		msg.WriteString("\r\n")
+	}
+	fmt.Fprintf(&msg, "--%s--\r\n", boundary)
+
+	return msg.Bytes(), nil
+}
+
+// Send a rendered email through SMTP. ctx bounds the whole exchange, a
+// stalled server gives up at its deadline instead of holding the caller.
+func Send(ctx context.Context, config *Config, to string, r *Rendered) error {
+	if !config.Configured() {
+		return fmt.Errorf("SMTP credentials not configured")
+	}
+
+	message, err := BuildMessage(config.From, to, r)
+	if err != nil {
+		return err
+	}
+
+	if err := send(ctx, config, to, message); err != nil {
+		return fmt.Errorf("failed to send email: %v", err)
+	}
+	return nil
+}
+
+// Same exchange as smtp.SendMail, on a connection bound to ctx. Implicit TLS
+// ports (465) are dialed with TLS, others are upgraded with STARTTLS.
+func send(ctx context.Context, config *Config, to string, message []byte) error {
+	client, err := dial(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Hello("localhost"); err != nil {
+		return err
+	}
+	if ok, _ := client.Extension("STARTTLS"); ok && !config.ImplicitTLS() {
+		if err := client.StartTLS(&tls.Config{ServerName: config.Host}); err != nil {
+			return err
+		}
+	}
+	if ok, _ := client.Extension("AUTH"); ok {
+		auth := smtp.PlainAuth("", config.Username, config.Password, config.Host)
+		if err := client.Auth(auth); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(config.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// Open an SMTP client whose connection gets ctx's deadline and is closed
+// when ctx is cancelled. On an implicit TLS port the handshake comes first,
+// the greeting is sent inside the TLS session.
+func dial(ctx context.Context, config *Config) (*smtp.Client, error) {
+	addr := net.JoinHostPort(config.Host, config.Port)
+	var conn net.Conn
+	var err error
+	if config.ImplicitTLS() {
+		dialer := tls.Dialer{Config: &tls.Config{ServerName: config.Host}}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	context.AfterFunc(ctx, func() { conn.Close() })
+
+	client, err := smtp.NewClient(conn, config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// Ping connects to the SMTP server the way Send does and waits for its
+// greeting, without logging in or sending anything
+func Ping(ctx context.Context, config *Config) error {
+	client, err := dial(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	return client.Quit()
+}
@@ -0,0 +1,40 @@
+package email
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestSendGivesUpOnStalledServer(t *testing.T) {
+	// Accepts connections but never sends the SMTP greeting
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	config := &Config{Host: host, Port: port, Username: "user", Password: "secret", From: "shop@example.com"}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err = Send(ctx, config, "customer@example.com", &Rendered{Subject: "Hi", Text: "Hi"})
+	if err == nil {
+		t.Fatal("Send() succeeded against a server that never answered")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Send() took %v, want it to stop at the context deadline", elapsed)
+	}
+}
@@ -0,0 +1,138 @@
+package email
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates/*.html templates/*.txt
+var templateFS embed.FS
+
+// Template names
+const (
+	TemplateOTP               = "otp"
+	TemplateOrderConfirmation = "order_confirmation"
+	TemplatePaymentReceived   = "payment_received"
+	TemplateOutForDelivery    = "out_for_delivery"
+	TemplateOrderCompleted    = "order_completed"
+	TemplateOrderCancelled    = "order_cancelled"
+	TemplateStockAlert        = "stock_alert"
+)
+
+// Names lists every template that can be rendered
+func Names() []string {
+	return []string{
+		TemplateOTP,
+		TemplateOrderConfirmation,
+		TemplatePaymentReceived,
+		TemplateOutForDelivery,
+		TemplateOrderCompleted,
+		TemplateOrderCancelled,
+		TemplateStockAlert,
+	}
+}
+
+// IsTemplate reports whether name is a known template
+func IsTemplate(name string) bool {
+	for _, n := range Names() {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// OTPData is the data for the otp template
+type OTPData struct {
+	Code             string
+	ExpiresInMinutes int
+}
+
+// OrderItemData is one line of an order email, amounts already formatted
+type OrderItemData struct {
+	Name     string
+	Quantity int
+	Subtotal string
+}
+
+// OrderData is the data for the order templates, amounts already formatted
+type OrderData struct {
+	CustomerName        string
+	OrderNumber         string
+	DeliveryDate        string
+	DeliveryLocation    string
+	PaymentMethod       string
+	Items               []OrderItemData
+	DeliveryFee         string
+	Total               string
+	AppreciationMessage string
+	CancellationReason  string
+	// TrackingURL is only set in the order confirmation
+	TrackingURL string
+}
+
+// StockAlertData is the data for the stock_alert template sent to admins
+type StockAlertData struct {
+	ProductName string
+	VariantName string
+	Stock       int
+	Threshold   int
+	SoldOut     bool
+}
+
+// Rendered is a rendered email ready to be sent
+type Rendered struct {
+	// Template it was rendered from, lets the outbox tell OTP emails apart
+	Template string
+	Subject  string
+	HTML     string
+	Text     string
+}
+
+// Each template is a layout plus its own content file, parsed once at startup
+type templatePair struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+var templates = mustParseTemplates()
+
+func mustParseTemplates() map[string]templatePair {
+	parsed := make(map[string]templatePair)
+	for _, name := range Names() {
+		html := htmltemplate.Must(htmltemplate.ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html"))
+		text := texttemplate.Must(texttemplate.ParseFS(templateFS, "templates/layout.txt", "templates/"+name+".txt"))
+		parsed[name] = templatePair{html: html, text: text}
+	}
+	return parsed
+}
+
+// Render a template by name
+func Render(name string, data interface{}) (*Rendered, error) {
+	pair, ok := templates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, html, text bytes.Buffer
+	if err := pair.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("render %s subject: %w", name, err)
+	}
+	if err := pair.html.ExecuteTemplate(&html, "layout", data); err != nil {
+		return nil, fmt.Errorf("render %s html: %w", name, err)
+	}
+	if err := pair.text.ExecuteTemplate(&text, "layout", data); err != nil {
+		return nil, fmt.Errorf("render %s text: %w", name, err)
+	}
+
+	return &Rendered{
+		Template: name,
+		Subject:  strings.TrimSpace(subject.String()),
+		HTML:     html.String(),
+		Text:     strings.TrimSpace(text.String()) + "\n",
+	}, nil
+}
@@ -0,0 +1,274 @@
+// Package escpos lays out receipts for thermal printers and renders them
+// as raw ESC/POS commands or as plain text.
+package escpos
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Characters per line of font A on common paper widths
+const (
+	Width58mm = 32
+	Width80mm = 48
+)
+
+// Align is the horizontal alignment of a line
+type Align byte
+
+const (
+	AlignLeft   Align = 0
+	AlignCenter Align = 1
+	AlignRight  Align = 2
+)
+
+type lineKind int
+
+const (
+	kindText lineKind = iota
+	kindRule
+	kindFeed
+	kindCut
+)
+
+type line struct {
+	kind  lineKind
+	text  string
+	align Align
+	bold  bool
+	large bool
+}
+
+// Receipt is a list of lines for a printer with Width characters per line
+type Receipt struct {
+	Width int
+	lines []line
+}
+
+// New creates an empty receipt, width is in characters
+func New(width int) *Receipt {
+	if width <= 0 {
+		width = Width58mm
+	}
+	return &Receipt{Width: width}
+}
+
+// Text adds s wrapped to the paper width
+func (r *Receipt) Text(s string) *Receipt {
+	return r.add(s, AlignLeft, false, false)
+}
+
+// Bold adds s in bold
+func (r *Receipt) Bold(s string) *Receipt {
+	return r.add(s, AlignLeft, true, false)
+}
+
+// Center adds s centered
+func (r *Receipt) Center(s string) *Receipt {
+	return r.add(s, AlignCenter, false, false)
+}
+
+// Title adds s centered in bold double size, half as many characters fit
+func (r *Receipt) Title(s string) *Receipt {
+	return r.add(s, AlignCenter, true, true)
+}
+
+// Indent adds s wrapped with every line indented by n spaces
+func (r *Receipt) Indent(n int, s string) *Receipt {
+	prefix := strings.Repeat(" ", n)
+	for _, l := range Wrap(s, r.Width-n) {
+		r.lines = append(r.lines, line{kind: kindText, text: prefix + l})
+	}
+	return r
+}
+
+// Columns adds left and right on one line, right aligned to the edge
+func (r *Receipt) Columns(left, right string) *Receipt {
+	left, right = ascii(left), ascii(right)
+	gap := r.Width - len(left) - len(right)
+	if gap < 1 {
+		r.Text(left)
+		return r.add(right, AlignRight, false, false)
+	}
+	r.lines = append(r.lines, line{kind: kindText, text: left + strings.Repeat(" ", gap) + right})
+	return r
+}
+
+// Rule adds a dashed line across the paper
+func (r *Receipt) Rule() *Receipt {
+	r.lines = append(r.lines, line{kind: kindRule})
+	return r
+}
+
+// Feed adds n empty lines
+func (r *Receipt) Feed(n int) *Receipt {
+	for i := 0; i < n; i++ {
+		r.lines = append(r.lines, line{kind: kindFeed})
+	}
+	return r
+}
+
+// Cut feeds the paper past the cutter and cuts it, ending one ticket
+func (r *Receipt) Cut() *Receipt {
+	r.lines = append(r.lines, line{kind: kindCut})
+	return r
+}
+
+func (r *Receipt) add(s string, align Align, bold, large bool) *Receipt {
+	width := r.Width
+	if large {
+		width /= 2
+	}
+	for _, l := range Wrap(s, width) {
+		r.lines = append(r.lines, line{kind: kindText, text: l, align: align, bold: bold, large: large})
+	}
+	return r
+}
+
+// ESC/POS commands
+var (
+	cmdInit      = []byte{0x1b, '@'}
+	cmdBoldOn    = []byte{0x1b, 'E', 1}
+	cmdBoldOff   = []byte{0x1b, 'E', 0}
+	cmdSizeLarge = []byte{0x1d, '!', 0x11}
+	cmdSizeOff   = []byte{0x1d, '!', 0x00}
+	cmdFeedCut   = []byte{0x1d, 'V', 66, 3}
+)
+
+// ESCPOS renders the receipt as printer commands
+func (r *Receipt) ESCPOS() []byte {
+	var buf bytes.Buffer
+	buf.Write(cmdInit)
+	for _, l := range r.lines {
+		switch l.kind {
+		case kindRule:
+			buf.WriteString(strings.Repeat("-", r.Width))
+			buf.WriteByte('\n')
+		case kindFeed:
+			buf.WriteByte('\n')
+		case kindCut:
+			buf.Write(cmdFeedCut)
+		default:
+			buf.Write([]byte{0x1b, 'a', byte(l.align)})
+			if l.bold {
+				buf.Write(cmdBoldOn)
+			}
+			if l.large {
+				buf.Write(cmdSizeLarge)
+			}
+			buf.WriteString(l.text)
+			buf.WriteByte('\n')
+			if l.large {
+				buf.Write(cmdSizeOff)
+			}
+			if l.bold {
+				buf.Write(cmdBoldOff)
+			}
+			buf.Write([]byte{0x1b, 'a', byte(AlignLeft)})
+		}
+	}
+	return buf.Bytes()
+}
+
+// PlainText renders the receipt as text, alignment done with spaces, each
+// cut becomes a scissors line
+func (r *Receipt) PlainText() string {
+	var sb strings.Builder
+	for _, l := range r.lines {
+		switch l.kind {
+		case kindRule:
+			sb.WriteString(strings.Repeat("-", r.Width))
+		case kindFeed:
+		case kindCut:
+			sb.WriteString("\n" + strings.Repeat("- ", r.Width/2-2) + "8<\n")
+		default:
+			text := l.text
+			if l.large {
+				text = strings.ToUpper(text)
+			}
+			pad := 0
+			switch l.align {
+			case AlignCenter:
+				pad = (r.Width - len(text)) / 2
+			case AlignRight:
+				pad = r.Width - len(text)
+			}
+			if pad > 0 {
+				sb.WriteString(strings.Repeat(" ", pad))
+			}
+			sb.WriteString(text)
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// Wrap splits s into lines of at most width characters, breaking on spaces
+// and cutting words longer than a line
+func Wrap(s string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(ascii(s), "\n") {
+		current := ""
+		for _, word := range strings.Fields(paragraph) {
+			for len(word) > width {
+				if current != "" {
+					lines = append(lines, current)
+					current = ""
+				}
+				lines = append(lines, word[:width])
+				word = word[width:]
+			}
+			switch {
+			case current == "":
+				current = word
+			case len(current)+1+len(word) <= width:
+				current += " " + word
+			default:
+				lines = append(lines, current)
+				current = word
+			}
+		}
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// Printers use their own code pages, keep to ASCII so every model prints
+// the same. Common accented letters lose their accent, anything else
+// becomes '?'.
+func ascii(s string) string {
+	var sb strings.Builder
+	for _, c := range s {
+		switch {
+		case c == '\t':
+			sb.WriteByte(' ')
+		case c >= 32 && c <= 126, c == '\n':
+			sb.WriteRune(c)
+		case c == '–' || c == '—':
+			sb.WriteByte('-')
+		case c == '‘' || c == '’':
+			sb.WriteByte('\'')
+		case c == '“' || c == '”':
+			sb.WriteByte('"')
+		case c == '\uFE0F' || c == '\u200D' || c == '\r':
+			// emoji variation selectors and joiners
+		default:
+			if base, ok := unaccented[c]; ok {
+				sb.WriteByte(base)
+			} else {
+				sb.WriteByte('?')
+			}
+		}
+	}
+	return sb.String()
+}
+
+var unaccented = map[rune]byte{
+	'à': 'a', 'á': 'a', 'â': 'a', 'ä': 'a', 'ã': 'a',
+	'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'ö': 'o', 'õ': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u',
+	'ñ': 'n', 'ç': 'c',
+	'À': 'A', 'Á': 'A', 'É': 'E', 'È': 'E', 'Í': 'I', 'Ó': 'O', 'Ú': 'U', 'Ñ': 'N', 'Ç': 'C',
+}
@@ -0,0 +1,274 @@
+// Package events runs the event showcase with its public comment threads.
+package events
+
+import (
+	"errors"
+	"log"
+
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler serves the events of an EventRepo
+type Handler struct {
+	repo repository.EventRepo
+}
+
+// NewHandler returns a Handler storing events and comments in repo
+func NewHandler(repo repository.EventRepo) *Handler {
+	return &Handler{repo: repo}
+}
+
+// Answer with status and message, or 503 while the database is down
+func fail(c *fiber.Ctx, err error, status int, message string) error {
+	if errors.Is(err, repository.ErrUnavailable) {
+		status, message = 503, "Database not connected"
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"success": false,
+		"message": message,
+	})
+}
+
+// RegisterRoutes mounts the public event pages and the admin event management
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	// Get all active events (public)
+	app.Get("/api/events", func(c *fiber.Ctx) error {
+		events, err := h.repo.ListActive()
+		if err != nil {
+			log.Printf("Error fetching events: %v", err)
+			return fail(c, err, 500, "Failed to fetch events")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    events,
+		})
+	})
+
+	// Get single event with comments (public)
+	app.Get("/api/events/:id", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		event, err := h.repo.FindActive(id)
+		if err != nil {
+			return fail(c, err, 404, "Event not found")
+		}
+
+		// Get comments (only top-level, replies will be nested)
+		comments, err := h.repo.Comments(id)
+		if err != nil {
+			log.Printf("Error fetching comments of event %s: %v", id, err)
+		}
+
+		// Get comment count
+		commentCount, err := h.repo.CountComments(id)
+		if err != nil {
+			log.Printf("Error counting comments of event %s: %v", id, err)
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data": models.EventWithComments{
+				Event:        *event,
+				CommentCount: int(commentCount),
+				Comments:     comments,
+			},
+		})
+	})
+
+	// Get replies for a comment (public)
+	app.Get("/api/events/:eventId/comments/:commentId/replies", func(c *fiber.Ctx) error {
+		replies, err := h.repo.Replies(c.Params("commentId"))
+		if err != nil {
+			log.Printf("Error fetching replies: %v", err)
+			return fail(c, err, 500, "Failed to fetch replies")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    replies,
+		})
+	})
+
+	// Add comment to event (public)
+	app.Post("/api/events/:id/comments", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var requestData struct {
+			CommenterName string  `json:"commenter_name"`
+			CommentText   string  `json:"comment_text"`
+			ParentID      *string `json:"parent_id"`
+		}
+
+		if err := c.BodyParser(&requestData); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+
+		// Validate
+		if requestData.CommenterName == "" || requestData.CommentText == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Name and comment are required",
+			})
+		}
+
+		comment := models.EventComment{
+			EventID:       id,
+			ParentID:      requestData.ParentID,
+			CommenterName: requestData.CommenterName,
+			CommentText:   requestData.CommentText,
+			IsAdmin:       false,
+		}
+
+		if err := h.repo.AddComment(&comment); err != nil {
+			log.Printf("Error creating comment: %v", err)
+			return fail(c, err, 500, "Failed to create comment")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    comment,
+			"message": "Comment added successfully",
+		})
+	})
+
+	// Admin: Get all events
+	app.Get("/api/admin/events", func(c *fiber.Ctx) error {
+		events, err := h.repo.List()
+		if err != nil {
+			log.Printf("Error fetching events: %v", err)
+			return fail(c, err, 500, "Failed to fetch events")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    events,
+		})
+	})
+
+	// Admin: Create event
+	app.Post("/api/admin/events", func(c *fiber.Ctx) error {
+		var event models.Event
+		if err := c.BodyParser(&event); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+
+		event.IsActive = true
+
+		if err := h.repo.Create(&event); err != nil {
+			log.Printf("Error creating event: %v", err)
+			return fail(c, err, 500, "Failed to create event")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    event,
+			"message": "Event created successfully",
+		})
+	})
+
+	// Admin: Update event
+	app.Put("/api/admin/events/:id", func(c *fiber.Ctx) error {
+		event, err := h.repo.Find(c.Params("id"))
+		if err != nil {
+			return fail(c, err, 404, "Event not found")
+		}
+
+		var updateData models.Event
+		if err := c.BodyParser(&updateData); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+
+		if err := h.repo.Update(event, updateData); err != nil {
+			log.Printf("Error updating event: %v", err)
+			return fail(c, err, 500, "Failed to update event")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    event,
+			"message": "Event updated successfully",
+		})
+	})
+
+	// Admin: Delete event
+	app.Delete("/api/admin/events/:id", func(c *fiber.Ctx) error {
+		if err := h.repo.Delete(c.Params("id")); err != nil {
+			log.Printf("Error deleting event: %v", err)
+			return fail(c, err, 500, "Failed to delete event")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Event deleted successfully",
+		})
+	})
+
+	// Admin: Add comment (verified)
+	app.Post("/api/admin/events/:id/comments", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		var requestData struct {
+			CommentText string  `json:"comment_text"`
+			ParentID    *string `json:"parent_id"`
+		}
+
+		if err := c.BodyParser(&requestData); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+
+		if requestData.CommentText == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Comment text is required",
+			})
+		}
+
+		comment := models.EventComment{
+			EventID:       id,
+			ParentID:      requestData.ParentID,
+			CommenterName: "SCAFF*FOOD",
+			CommentText:   requestData.CommentText,
+			IsAdmin:       true,
+		}
+
+		if err := h.repo.AddComment(&comment); err != nil {
+			log.Printf("Error creating admin comment: %v", err)
+			return fail(c, err, 500, "Failed to create comment")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    comment,
+			"message": "Comment added successfully",
+		})
+	})
+
+	// Admin: Delete comment
+	app.Delete("/api/admin/events/:eventId/comments/:commentId", func(c *fiber.Ctx) error {
+		if err := h.repo.DeleteComment(c.Params("commentId")); err != nil {
+			log.Printf("Error deleting comment: %v", err)
+			return fail(c, err, 500, "Failed to delete comment")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Comment deleted successfully",
+		})
+	})
+}
@@ -0,0 +1,117 @@
+package events
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newApp(repo repository.EventRepo) *fiber.App {
+	app := fiber.New()
+	NewHandler(repo).RegisterRoutes(app)
+	return app
+}
+
+func do(t *testing.T, app *fiber.App, method, target, body string) (int, map[string]interface{}) {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, target, err)
+	}
+	var payload map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("%s %s: decode body: %v", method, target, err)
+	}
+	return resp.StatusCode, payload
+}
+
+func TestPublicEventsHideInactive(t *testing.T) {
+	repo := repository.NewMemoryEventRepo(
+		models.Event{Title: "Bazar", ImageURL: "/produk/bazar.jpg", IsActive: true},
+		models.Event{Title: "Draft", ImageURL: "/produk/draft.jpg"},
+	)
+	app := newApp(repo)
+
+	status, payload := do(t, app, "GET", "/api/events", "")
+	if status != 200 {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	events := payload["data"].([]interface{})
+	if len(events) != 1 || events[0].(map[string]interface{})["title"] != "Bazar" {
+		t.Fatalf("public events = %v, want only Bazar", events)
+	}
+
+	_, payload = do(t, app, "GET", "/api/admin/events", "")
+	if got := len(payload["data"].([]interface{})); got != 2 {
+		t.Errorf("admin events = %d, want 2", got)
+	}
+}
+
+func TestCommentThread(t *testing.T) {
+	event := models.Event{Title: "Bazar", ImageURL: "/produk/bazar.jpg", IsActive: true}
+	repo := repository.NewMemoryEventRepo()
+	repo.Create(&event)
+	app := newApp(repo)
+
+	status, payload := do(t, app, "POST", "/api/events/"+event.ID+"/comments",
+		`{"commenter_name":"Sari","comment_text":"Jam berapa buka?"}`)
+	if status != 200 {
+		t.Fatalf("add comment: status = %d, body = %v", status, payload)
+	}
+	commentID := payload["data"].(map[string]interface{})["id"].(string)
+
+	status, payload = do(t, app, "POST", "/api/admin/events/"+event.ID+"/comments",
+		`{"comment_text":"Jam 10 pagi","parent_id":"`+commentID+`"}`)
+	if status != 200 {
+		t.Fatalf("admin reply: status = %d, body = %v", status, payload)
+	}
+	if payload["data"].(map[string]interface{})["commenter_name"] != "SCAFF*FOOD" {
+		t.Errorf("admin reply not signed as SCAFF*FOOD: %v", payload["data"])
+	}
+
+	_, payload = do(t, app, "GET", "/api/events/"+event.ID, "")
+	data := payload["data"].(map[string]interface{})
+	if data["comment_count"] != float64(2) {
+		t.Errorf("comment_count = %v, want 2", data["comment_count"])
+	}
+	if got := len(data["comments"].([]interface{})); got != 1 {
+		t.Errorf("top-level comments = %d, want 1", got)
+	}
+
+	_, payload = do(t, app, "GET", "/api/events/"+event.ID+"/comments/"+commentID+"/replies", "")
+	if got := len(payload["data"].([]interface{})); got != 1 {
+		t.Errorf("replies = %d, want 1", got)
+	}
+}
+
+func TestCommentRequiresNameAndText(t *testing.T) {
+	app := newApp(repository.NewMemoryEventRepo())
+
+	status, _ := do(t, app, "POST", "/api/events/any/comments", `{"commenter_name":"Sari"}`)
+	if status != 400 {
+		t.Errorf("status = %d, want 400", status)
+	}
+}
+
+func TestUpdateUnknownEvent(t *testing.T) {
+	app := newApp(repository.NewMemoryEventRepo())
+
+	status, _ := do(t, app, "PUT", "/api/admin/events/missing", `{"title":"x"}`)
+	if status != 404 {
+		t.Errorf("status = %d, want 404", status)
+	}
+}
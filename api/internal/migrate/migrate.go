@@ -0,0 +1,410 @@
+// Package migrate applies the numbered SQL files of migrations/ and records
+// them in the schema_migrations table.
+//
+// A migration file is named NNN_description.sql. Everything above an optional
+// "-- +migrate Down" line is the up section, everything below it undoes it.
+// The version of a migration is its whole file name without .sql, so two files
+// sharing a number (the two 009 files) are still distinct migrations.
+//
+// Migrations 001 to 015 predate the runner and have no down section, so
+// rolling back stops at 015.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Key of the Postgres advisory lock held while migrating, so only one of
+// several booting instances applies migrations at a time
+const lockKey int64 = 4_620_195_012
+
+const (
+	upMarker   = "-- +migrate Up"
+	downMarker = "-- +migrate Down"
+)
+
+var (
+	fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.sql$`)
+	nonWordPattern  = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// ErrUnversioned is returned by Up when the database already has tables but
+// no migration history, e.g. a schema applied by hand before the runner
+// existed. Run "migrate baseline" once to record what is already there.
+var ErrUnversioned = errors.New("database has tables but no schema_migrations history, run `migrate baseline <number>` first")
+
+// Migration is one SQL file of migrations/
+type Migration struct {
+	Version  string
+	Number   int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+// Status of a migration in the database
+type Status struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+	// Modified is set when the up section changed after it was applied
+	Modified bool
+}
+
+type appliedRow struct {
+	checksum  string
+	appliedAt time.Time
+}
+
+// Load reads and parses every migration file of fsys, ordered by number and
+// then by file name. Two files differing only in zero padding, e.g. 9_x.sql
+// and 009_x.sql, are rejected as duplicates.
+func Load(fsys fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	migrations := make([]Migration, 0, len(names))
+	seen := make(map[string]string, len(names))
+	for _, name := range names {
+		match := fileNamePattern.FindStringSubmatch(name)
+		if match == nil {
+			return nil, fmt.Errorf("migration %s: file name must look like 001_description.sql", name)
+		}
+		number, _ := strconv.Atoi(match[1])
+
+		key := fmt.Sprintf("%d_%s", number, match[2])
+		if other, ok := seen[key]; ok {
+			return nil, fmt.Errorf("migration %s: duplicate of %s", name, other)
+		}
+		seen[key] = name
+
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		up, down := split(string(content))
+		if up == "" {
+			return nil, fmt.Errorf("migration %s: up section is empty", name)
+		}
+
+		migrations = append(migrations, Migration{
+			Version:  strings.TrimSuffix(name, ".sql"),
+			Number:   number,
+			Name:     match[2],
+			Up:       up,
+			Down:     down,
+			Checksum: checksum(up),
+		})
+	}
+
+	sort.SliceStable(migrations, func(i, j int) bool {
+		if migrations[i].Number != migrations[j].Number {
+			return migrations[i].Number < migrations[j].Number
+		}
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Split a file into its up and down sections
+func split(content string) (string, string) {
+	var up, down strings.Builder
+	current := &up
+	for _, line := range strings.SplitAfter(content, "\n") {
+		switch strings.TrimSpace(line) {
+		case upMarker:
+			current = &up
+			continue
+		case downMarker:
+			current = &down
+			continue
+		}
+		current.WriteString(line)
+	}
+	return strings.TrimSpace(up.String()), strings.TrimSpace(down.String())
+}
+
+// Only the up section is checksummed, adding a down section to an applied
+// migration later does not count as modifying it
+func checksum(up string) string {
+	sum := sha256.Sum256([]byte(up))
+	return hex.EncodeToString(sum[:])
+}
+
+// Migrator applies a set of migrations to one database
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// New loads the migrations of fsys for db
+func New(db *sql.DB, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+func ensureTable(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version VARCHAR(255) PRIMARY KEY,
+			checksum CHAR(64) NOT NULL,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`)
+	return err
+}
+
+func loadApplied(ctx context.Context, conn *sql.Conn) (map[string]appliedRow, error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[string]appliedRow)
+	for rows.Next() {
+		var version string
+		var row appliedRow
+		if err := rows.Scan(&version, &row.checksum, &row.appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = row
+	}
+	return applied, rows.Err()
+}
+
+// Run fn on a single connection holding the advisory lock, with
+// schema_migrations created and its rows loaded
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn, applied map[string]appliedRow) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// Blocks until a concurrent migrator releases the lock
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockKey); err != nil {
+			log.Printf("⚠️  Failed to release migration lock: %v", err)
+		}
+	}()
+
+	if err := ensureTable(ctx, conn); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	applied, err := loadApplied(ctx, conn)
+	if err != nil {
+		return fmt.Errorf("read schema_migrations: %w", err)
+	}
+	return fn(conn, applied)
+}
+
+// Refuse to go on when an applied migration was edited afterwards
+func (m *Migrator) verify(applied map[string]appliedRow) error {
+	for _, migration := range m.migrations {
+		row, ok := applied[migration.Version]
+		if ok && strings.TrimSpace(row.checksum) != migration.Checksum {
+			return fmt.Errorf("migration %s was modified after it was applied", migration.Version)
+		}
+	}
+	return nil
+}
+
+// Status lists every migration and whether it is applied
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var statuses []Status
+	err := m.withLock(ctx, func(conn *sql.Conn, applied map[string]appliedRow) error {
+		for _, migration := range m.migrations {
+			status := Status{Migration: migration}
+			if row, ok := applied[migration.Version]; ok {
+				status.Applied = true
+				status.AppliedAt = row.appliedAt
+				status.Modified = strings.TrimSpace(row.checksum) != migration.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Up applies every pending migration, each in its own transaction, and
+// returns how many were applied
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn, applied map[string]appliedRow) error {
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		if len(applied) == 0 {
+			var existing bool
+			err := conn.QueryRowContext(ctx, "SELECT to_regclass('public.users') IS NOT NULL").Scan(&existing)
+			if err != nil {
+				return err
+			}
+			if existing {
+				return ErrUnversioned
+			}
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			log.Printf("✅ Applied migration %s", migration.Version)
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+		return fmt.Errorf("migration %s: %w", migration.Version, err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, checksum) VALUES ($1, $2)",
+		migration.Version, migration.Checksum); err != nil {
+		return fmt.Errorf("record migration %s: %w", migration.Version, err)
+	}
+	return tx.Commit()
+}
+
+// Down rolls back the last steps applied migrations, newest first, and
+// returns how many were rolled back
+func (m *Migrator) Down(ctx context.Context, steps int) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn, applied map[string]appliedRow) error {
+		if err := m.verify(applied); err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && count < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %s has no down section", migration.Version)
+			}
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			log.Printf("↩️  Rolled back migration %s", migration.Version)
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+		return fmt.Errorf("migration %s: %w", migration.Version, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version); err != nil {
+		return fmt.Errorf("unrecord migration %s: %w", migration.Version, err)
+	}
+	return tx.Commit()
+}
+
+// Baseline records every migration numbered up to number as applied without
+// running it, for databases whose schema was applied by hand
+func (m *Migrator) Baseline(ctx context.Context, number int) (int, error) {
+	count := 0
+	err := m.withLock(ctx, func(conn *sql.Conn, applied map[string]appliedRow) error {
+		for _, migration := range m.migrations {
+			if migration.Number > number {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			if _, err := conn.ExecContext(ctx,
+				"INSERT INTO schema_migrations (version, checksum) VALUES ($1, $2)",
+				migration.Version, migration.Checksum); err != nil {
+				return fmt.Errorf("record migration %s: %w", migration.Version, err)
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Latest returns the highest migration number, 0 without migrations
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Number
+}
+
+// Create writes an empty migration numbered after the last one in dir and
+// returns its path
+func Create(dir, description string) (string, error) {
+	name := strings.Trim(nonWordPattern.ReplaceAllString(strings.ToLower(description), "_"), "_")
+	if name == "" {
+		return "", fmt.Errorf("migration description is required")
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", err
+	}
+	next := 1
+	if len(migrations) > 0 {
+		next = migrations[len(migrations)-1].Number + 1
+	}
+
+	filename := filepath.Join(dir, fmt.Sprintf("%03d_%s.sql", next, name))
+	file, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	template := fmt.Sprintf("-- %s\n%s\n\n\n%s\n\n", strings.ReplaceAll(name, "_", " "), upMarker, downMarker)
+	if _, err := file.WriteString(template); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
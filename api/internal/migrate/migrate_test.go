@@ -0,0 +1,214 @@
+package migrate
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"scaff-food-backend/migrations"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		up, down string
+	}{
+		{
+			name:    "up only",
+			content: "CREATE TABLE a (id INT);\n",
+			up:      "CREATE TABLE a (id INT);",
+		},
+		{
+			name:    "down section",
+			content: "CREATE TABLE a (id INT);\n\n-- +migrate Down\nDROP TABLE a;\n",
+			up:      "CREATE TABLE a (id INT);",
+			down:    "DROP TABLE a;",
+		},
+		{
+			name:    "explicit up marker",
+			content: "-- +migrate Up\nCREATE TABLE a (id INT);\n-- +migrate Down\nDROP TABLE a;",
+			up:      "CREATE TABLE a (id INT);",
+			down:    "DROP TABLE a;",
+		},
+		{
+			name:    "indented markers",
+			content: "  -- +migrate Up  \nCREATE TABLE a (id INT);\n\t-- +migrate Down\nDROP TABLE a;\n",
+			up:      "CREATE TABLE a (id INT);",
+			down:    "DROP TABLE a;",
+		},
+		{
+			name:    "marker inside a line is not a marker",
+			content: "SELECT '-- +migrate Down';\n",
+			up:      "SELECT '-- +migrate Down';",
+		},
+		{
+			name:    "empty down section",
+			content: "CREATE TABLE a (id INT);\n-- +migrate Down\n\n",
+			up:      "CREATE TABLE a (id INT);",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			up, down := split(test.content)
+			if up != test.up || down != test.down {
+				t.Errorf("split() = %q, %q, want %q, %q", up, down, test.up, test.down)
+			}
+		})
+	}
+}
+
+func TestChecksumIgnoresDownSection(t *testing.T) {
+	before, _ := split("CREATE TABLE a (id INT);\n")
+	after, _ := split("CREATE TABLE a (id INT);\n\n-- +migrate Down\nDROP TABLE a;\n")
+	if checksum(before) != checksum(after) {
+		t.Error("adding a down section changed the checksum")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	migrator := &Migrator{migrations: []Migration{
+		{Version: "001_a", Checksum: checksum("CREATE TABLE a (id INT);")},
+		{Version: "002_b", Checksum: checksum("CREATE TABLE b (id INT);")},
+	}}
+
+	tests := []struct {
+		name    string
+		applied map[string]appliedRow
+		wantErr string
+	}{
+		{
+			name:    "nothing applied",
+			applied: map[string]appliedRow{},
+		},
+		{
+			name: "matching checksums",
+			applied: map[string]appliedRow{
+				"001_a": {checksum: checksum("CREATE TABLE a (id INT);")},
+				"002_b": {checksum: checksum("CREATE TABLE b (id INT);")},
+			},
+		},
+		{
+			name: "padded checksum column",
+			applied: map[string]appliedRow{
+				"001_a": {checksum: checksum("CREATE TABLE a (id INT);") + "  "},
+			},
+		},
+		{
+			name: "modified after it was applied",
+			applied: map[string]appliedRow{
+				"001_a": {checksum: checksum("CREATE TABLE a (id INT);")},
+				"002_b": {checksum: checksum("CREATE TABLE b (id BIGINT);")},
+			},
+			wantErr: "002_b",
+		},
+		{
+			name: "applied version no longer on disk",
+			applied: map[string]appliedRow{
+				"003_c": {checksum: "whatever"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := migrator.verify(test.applied)
+			switch {
+			case test.wantErr == "" && err != nil:
+				t.Errorf("verify() = %v", err)
+			case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+				t.Errorf("verify() = %v, want an error about %s", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func file(content string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(content)}
+}
+
+func TestLoadOrdersByNumberThenName(t *testing.T) {
+	migrations, err := Load(fstest.MapFS{
+		"10_later.sql":                   file("SELECT 10;"),
+		"009_add_product_variants.sql":   file("SELECT 9;\n-- +migrate Down\nSELECT -9;"),
+		"002_second.sql":                 file("SELECT 2;"),
+		"009_add_product_conditions.sql": file("SELECT 9;"),
+		"001_first.sql":                  file("SELECT 1;"),
+		"README.md":                      file("not a migration"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var versions []string
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	want := "001_first 002_second 009_add_product_conditions 009_add_product_variants 10_later"
+	if got := strings.Join(versions, " "); got != want {
+		t.Errorf("versions = %s, want %s", got, want)
+	}
+
+	variants := migrations[3]
+	if variants.Number != 9 || variants.Name != "add_product_variants" || variants.Down != "SELECT -9;" {
+		t.Errorf("migration = %+v", variants)
+	}
+	if variants.Checksum != checksum("SELECT 9;") {
+		t.Errorf("checksum = %s", variants.Checksum)
+	}
+}
+
+func TestLoadRejectsBadFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		wantErr string
+	}{
+		{
+			name: "duplicate number and name",
+			fsys: fstest.MapFS{
+				"9_add_x.sql":   file("SELECT 1;"),
+				"009_add_x.sql": file("SELECT 2;"),
+			},
+			wantErr: "duplicate",
+		},
+		{
+			name:    "no number",
+			fsys:    fstest.MapFS{"add_x.sql": file("SELECT 1;")},
+			wantErr: "file name",
+		},
+		{
+			name:    "upper case name",
+			fsys:    fstest.MapFS{"001_AddX.sql": file("SELECT 1;")},
+			wantErr: "file name",
+		},
+		{
+			name:    "empty up section",
+			fsys:    fstest.MapFS{"001_add_x.sql": file("-- +migrate Down\nDROP TABLE x;")},
+			wantErr: "up section is empty",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := Load(test.fsys)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("Load() = %v, want an error about %s", err, test.wantErr)
+			}
+		})
+	}
+}
+
+// Rolling back works down to 015, the schema from before the runner
+func TestShippedMigrationsCanBeRolledBack(t *testing.T) {
+	shipped, err := Load(migrations.FS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, migration := range shipped {
+		if migration.Number > 15 && migration.Down == "" {
+			t.Errorf("migration %s has no down section", migration.Version)
+		}
+	}
+}
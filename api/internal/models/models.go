@@ -0,0 +1,256 @@
+// Package models holds the table models shared by every part of the service,
+// matching the SQL in migrations/. Tables only one package works with keep
+// their model there: outbox_messages (outbox), webhooks (webhook) and
+// order_events (realtime).
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// User model
+type User struct {
+	ID        string    `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Email     string    `gorm:"unique;not null" json:"email"`
+	Name      string    `json:"name"`
+	Phone     string    `json:"phone"`
+	Role      string    `gorm:"default:'customer'" json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Product model
+type Product struct {
+	ID                  string           `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	SKU                 *string          `gorm:"column:sku" json:"sku,omitempty"`
+	Name                string           `gorm:"not null" json:"name"`
+	ShortDescription    string           `json:"short_description"`
+	Description         string           `json:"description"`
+	Price               float64          `gorm:"not null" json:"price"`
+	Category            string           `json:"category"`
+	Tag                 string           `json:"tag"`
+	TagColor            string           `json:"tag_color"`
+	ImageURL1           string           `gorm:"column:image_url_1" json:"image_url_1"`
+	ImageURL2           string           `gorm:"column:image_url_2" json:"image_url_2"`
+	ImageURL3           string           `gorm:"column:image_url_3" json:"image_url_3"`
+	Stock               int              `gorm:"default:0" json:"stock"`
+	LowStockThreshold   int              `gorm:"default:5" json:"low_stock_threshold"`
+	IsAvailable         bool             `gorm:"default:true" json:"is_available"`
+	AutoDisabled        bool             `gorm:"default:false" json:"auto_disabled"`
+	MinOrder            int              `gorm:"default:1" json:"min_order"`
+	MinOrderTB          int              `gorm:"column:min_order_tb;default:1" json:"min_order_tb"`
+	MinOrderLuarTB      int              `gorm:"column:min_order_luar_tb;default:1" json:"min_order_luar_tb"`
+	AvailableDaysTB     pq.StringArray   `gorm:"column:available_days_tb;type:text[]" json:"available_days_tb"`
+	AvailableDaysLuarTB pq.StringArray   `gorm:"column:available_days_luar_tb;type:text[]" json:"available_days_luar_tb"`
+	Conditions          string           `gorm:"type:jsonb;default:'[]'" json:"conditions,omitempty"`
+	Addons              string           `gorm:"type:jsonb;default:'[]'" json:"addons,omitempty"`
+	QRISId              *string          `gorm:"type:uuid" json:"qris_id,omitempty"`
+	Variants            []ProductVariant `gorm:"foreignKey:ProductID" json:"variants,omitempty"`
+	CreatedAt           time.Time        `json:"created_at"`
+	UpdatedAt           time.Time        `json:"updated_at"`
+}
+
+// ProductVariant model
+type ProductVariant struct {
+	ID           string    `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ProductID    string    `gorm:"type:uuid;not null" json:"product_id"`
+	Name         string    `gorm:"not null" json:"name"`
+	Price        float64   `gorm:"not null" json:"price"`
+	Stock        int       `gorm:"default:0" json:"stock"`
+	IsAvailable  bool      `gorm:"default:true" json:"is_available"`
+	AutoDisabled bool      `gorm:"default:false" json:"auto_disabled"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// QRISCode model
+type QRISCode struct {
+	ID        string    `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	ImageURL  string    `gorm:"type:text;not null" json:"image_url"`
+	IsActive  bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Event model
+type Event struct {
+	ID          string    `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Title       string    `gorm:"not null" json:"title"`
+	Description string    `gorm:"type:text" json:"description"`
+	ImageURL    string    `gorm:"type:text;not null" json:"image_url"`
+	MusicURL    string    `gorm:"type:text" json:"music_url,omitempty"`
+	MusicTitle  string    `json:"music_title,omitempty"`
+	IsActive    bool      `gorm:"default:true" json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// EventComment model
+type EventComment struct {
+	ID            string    `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	EventID       string    `gorm:"type:uuid;not null" json:"event_id"`
+	ParentID      *string   `gorm:"type:uuid" json:"parent_id,omitempty"`
+	CommenterName string    `gorm:"not null" json:"commenter_name"`
+	CommentText   string    `gorm:"type:text;not null" json:"comment_text"`
+	IsAdmin       bool      `gorm:"default:false" json:"is_admin"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// EventWithComments for response
+type EventWithComments struct {
+	Event
+	CommentCount int            `json:"comment_count"`
+	Comments     []EventComment `json:"comments,omitempty"`
+}
+
+// Order model
+type Order struct {
+	ID                  string     `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID              *string    `gorm:"type:uuid" json:"user_id,omitempty"`
+	OrderNumber         string     `gorm:"unique;not null" json:"order_number"`
+	CustomerName        string     `gorm:"not null" json:"customer_name"`
+	CustomerEmail       string     `gorm:"not null" json:"customer_email"`
+	CustomerPhone       string     `gorm:"not null" json:"customer_phone"`
+	DeliveryAddress     string     `json:"delivery_address"`
+	DeliveryLocation    string     `gorm:"default:'TB'" json:"delivery_location"`
+	DeliveryDate        *time.Time `gorm:"type:date" json:"delivery_date,omitempty"`
+	Notes               string     `json:"notes,omitempty"`
+	Subtotal            float64    `json:"subtotal"`
+	DeliveryFee         float64    `json:"delivery_fee"`
+	Total               float64    `json:"total"`
+	PaymentMethod       string     `json:"payment_method"`
+	PaymentStatus       string     `gorm:"default:'pending'" json:"payment_status"`
+	PaymentProof        string     `json:"payment_proof,omitempty"`
+	OrderStatus         string     `gorm:"default:'pending'" json:"order_status"`
+	DeliveryPhoto       string     `json:"delivery_photo,omitempty"`
+	AppreciationMessage string     `json:"appreciation_message,omitempty"`
+	CancellationReason  string     `json:"cancellation_reason,omitempty"`
+	CancelledAt         *time.Time `json:"cancelled_at,omitempty"`
+	TrackingToken       string     `gorm:"unique" json:"-"`
+	CourierID           *string    `gorm:"type:uuid" json:"courier_id,omitempty"`
+	DeliveryBatchID     *string    `gorm:"type:uuid" json:"delivery_batch_id,omitempty"`
+	DeliverySequence    int        `json:"delivery_sequence,omitempty"`
+	DeliveryNote        string     `json:"delivery_note,omitempty"`
+	DeliveredAt         *time.Time `json:"delivered_at,omitempty"`
+	InvoiceNumber       string     `gorm:"<-:false" json:"invoice_number,omitempty"`
+	InvoicedAt          *time.Time `gorm:"<-:false" json:"invoiced_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// OrderItem model
+type OrderItem struct {
+	ID            string         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	OrderID       string         `gorm:"type:uuid;not null" json:"order_id"`
+	ProductID     string         `gorm:"type:uuid" json:"product_id"`
+	VariantID     *string        `gorm:"type:uuid" json:"variant_id,omitempty"`
+	ProductName   string         `gorm:"not null" json:"product_name"`
+	VariantName   string         `json:"variant_name,omitempty"`
+	ConditionName string         `json:"condition_name,omitempty"`
+	Addons        pq.StringArray `gorm:"type:text[]" json:"addons,omitempty"`
+	ProductPrice  float64        `gorm:"not null" json:"product_price"`
+	ProductImage  string         `json:"product_image"`
+	Quantity      int            `gorm:"not null" json:"quantity"`
+	Subtotal      float64        `gorm:"not null" json:"subtotal"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+// OrderWithItems for response
+type OrderWithItems struct {
+	Order
+	Items []OrderItem `json:"items"`
+}
+
+// Setting model, a key/value pair editable from the admin panel
+type Setting struct {
+	ID          string    `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Key         string    `gorm:"unique;not null" json:"key"`
+	Value       string    `json:"value"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CustomerAddress model
+type CustomerAddress struct {
+	ID               string    `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID           string    `gorm:"type:uuid;not null" json:"user_id"`
+	Label            string    `json:"label"`
+	RecipientName    string    `json:"recipient_name"`
+	RecipientPhone   string    `json:"recipient_phone"`
+	Address          string    `gorm:"type:text;not null" json:"address"`
+	DeliveryLocation string    `gorm:"default:'TB'" json:"delivery_location"`
+	IsDefault        bool      `gorm:"default:false" json:"is_default"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// InventoryMovement is one stock change of a product or variant. Quantity
+// is signed and is what was actually applied, stock never goes below zero.
+type InventoryMovement struct {
+	ID          string    `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ProductID   string    `gorm:"type:uuid;not null" json:"product_id"`
+	VariantID   *string   `gorm:"type:uuid" json:"variant_id,omitempty"`
+	Type        string    `gorm:"not null" json:"type"`
+	Quantity    int       `gorm:"not null" json:"quantity"`
+	StockBefore int       `json:"stock_before"`
+	StockAfter  int       `json:"stock_after"`
+	Reason      string    `json:"reason,omitempty"`
+	Actor       string    `json:"actor"`
+	OrderID     *string   `gorm:"type:uuid" json:"order_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Ingredient is a raw material with its cost per unit, e.g. flour per gram
+type Ingredient struct {
+	ID          string    `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name        string    `gorm:"unique;not null" json:"name"`
+	Unit        string    `gorm:"not null" json:"unit"`
+	CostPerUnit float64   `gorm:"not null" json:"cost_per_unit"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Recipe is the quantity of one ingredient used per unit of a product, or
+// of one of its variants. Variants without their own recipe use the product's.
+type Recipe struct {
+	ID           string      `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	ProductID    string      `gorm:"type:uuid;not null" json:"product_id"`
+	VariantID    *string     `gorm:"type:uuid" json:"variant_id,omitempty"`
+	IngredientID string      `gorm:"type:uuid;not null" json:"ingredient_id"`
+	Quantity     float64     `gorm:"not null" json:"quantity"`
+	Ingredient   *Ingredient `gorm:"foreignKey:IngredientID" json:"ingredient,omitempty"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+// DeliveryBatch is one courier run on a delivery date
+type DeliveryBatch struct {
+	ID           string     `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name         string     `gorm:"not null" json:"name"`
+	CourierID    *string    `gorm:"type:uuid" json:"courier_id,omitempty"`
+	DeliveryDate *time.Time `gorm:"type:date" json:"delivery_date,omitempty"`
+	Notes        string     `json:"notes,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// Order statuses counted as active on the dashboard
+var ActiveOrderStatuses = []string{"pending", "processing", "on_delivery"}
+
+// Order statuses that never count towards customers
+var CancelledOrderStatuses = []string{"cancelled", "dibatalkan"}
+
+// IsCancelledStatus reports whether an order status means cancelled
+func IsCancelledStatus(status string) bool {
+	for _, s := range CancelledOrderStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package notification
+
+import (
+	"context"
+	"log"
+)
+
+// Message is a templated notification for one recipient
+type Message struct {
+	To       string
+	Template string
+	Language string
+	Params   []string
+}
+
+// Notifier delivers messages through one channel (WhatsApp, email, ...)
+type Notifier interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogNotifier only logs messages, used when no channel is configured
+type LogNotifier struct{}
+
+func (LogNotifier) Name() string {
+	return "log"
+}
+
+func (LogNotifier) Send(ctx context.Context, msg Message) error {
+	log.Printf("[NOTIFY] %s -> %s %v (no channel configured)", msg.Template, msg.To, msg.Params)
+	return nil
+}
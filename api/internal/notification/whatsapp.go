@@ -0,0 +1,162 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultWhatsAppBaseURL    = "https://graph.facebook.com"
+	DefaultWhatsAppAPIVersion = "v21.0"
+	DefaultWhatsAppLanguage   = "id"
+)
+
+// WhatsAppConfig holds WhatsApp Business Cloud API credentials
+type WhatsAppConfig struct {
+	BaseURL       string
+	APIVersion    string
+	PhoneNumberID string
+	AccessToken   string
+	Language      string
+	Timeout       time.Duration
+}
+
+// WhatsAppNotifier sends template messages through the WhatsApp Business Cloud API
+type WhatsAppNotifier struct {
+	config WhatsAppConfig
+	client *http.Client
+}
+
+// APIError is an error response from the Cloud API
+type APIError struct {
+	StatusCode int
+	Code       int    `json:"code"`
+	Type       string `json:"type"`
+	Message    string `json:"message"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("whatsapp api: status %d code %d: %s", e.StatusCode, e.Code, e.Message)
+}
+
+func NewWhatsAppNotifier(config WhatsAppConfig) *WhatsAppNotifier {
+	if config.BaseURL == "" {
+		config.BaseURL = DefaultWhatsAppBaseURL
+	}
+	if config.APIVersion == "" {
+		config.APIVersion = DefaultWhatsAppAPIVersion
+	}
+	if config.Language == "" {
+		config.Language = DefaultWhatsAppLanguage
+	}
+	if config.Timeout == 0 {
+		config.Timeout = 10 * time.Second
+	}
+	return &WhatsAppNotifier{
+		config: config,
+		client: &http.Client{Timeout: config.Timeout},
+	}
+}
+
+func (w *WhatsAppNotifier) Name() string {
+	return "whatsapp"
+}
+
+// Request body types for POST /{phone-number-id}/messages
+type waTextParam struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type waComponent struct {
+	Type       string        `json:"type"`
+	Parameters []waTextParam `json:"parameters"`
+}
+
+type waLanguage struct {
+	Code string `json:"code"`
+}
+
+type waTemplate struct {
+	Name       string        `json:"name"`
+	Language   waLanguage    `json:"language"`
+	Components []waComponent `json:"components,omitempty"`
+}
+
+type waMessage struct {
+	MessagingProduct string     `json:"messaging_product"`
+	To               string     `json:"to"`
+	Type             string     `json:"type"`
+	Template         waTemplate `json:"template"`
+}
+
+// Send a template message, msg.To is an E.164 phone number
+func (w *WhatsAppNotifier) Send(ctx context.Context, msg Message) error {
+	to := strings.TrimPrefix(msg.To, "+")
+	if to == "" {
+		return fmt.Errorf("whatsapp: empty recipient")
+	}
+
+	language := msg.Language
+	if language == "" {
+		language = w.config.Language
+	}
+
+	body := waMessage{
+		MessagingProduct: "whatsapp",
+		To:               to,
+		Type:             "template",
+		Template: waTemplate{
+			Name:     msg.Template,
+			Language: waLanguage{Code: language},
+		},
+	}
+	if len(msg.Params) > 0 {
+		params := make([]waTextParam, len(msg.Params))
+		for i, p := range msg.Params {
+			params[i] = waTextParam{Type: "text", Text: p}
+		}
+		body.Template.Components = []waComponent{{Type: "body", Parameters: params}}
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/%s/%s/messages", strings.TrimRight(w.config.BaseURL, "/"), w.config.APIVersion, w.config.PhoneNumberID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+w.config.AccessToken)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("whatsapp: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	var errResp struct {
+		Error APIError `json:"error"`
+	}
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(respBody))}
+	if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
+		apiErr.Code = errResp.Error.Code
+		apiErr.Type = errResp.Error.Type
+		apiErr.Message = errResp.Error.Message
+	}
+	return apiErr
+}
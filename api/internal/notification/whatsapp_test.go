@@ -0,0 +1,90 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWhatsAppNotifierSendsTemplate(t *testing.T) {
+	var gotPath, gotAuth string
+	var got waMessage
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"messaging_product":"whatsapp","messages":[{"id":"wamid.1"}]}`))
+	}))
+	defer server.Close()
+
+	notifier := NewWhatsAppNotifier(WhatsAppConfig{
+		BaseURL:       server.URL,
+		APIVersion:    "v21.0",
+		PhoneNumberID: "12345",
+		AccessToken:   "secret",
+	})
+
+	err := notifier.Send(context.Background(), Message{
+		To:       "+6281234567890",
+		Template: "order_created",
+		Params:   []string{"Budi", "ORD-20250101-001"},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if gotPath != "/v21.0/12345/messages" {
+		t.Errorf("path = %q", gotPath)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("authorization = %q", gotAuth)
+	}
+	if got.To != "6281234567890" {
+		t.Errorf("to = %q, want number without +", got.To)
+	}
+	if got.MessagingProduct != "whatsapp" || got.Type != "template" {
+		t.Errorf("unexpected envelope: %+v", got)
+	}
+	if got.Template.Name != "order_created" || got.Template.Language.Code != DefaultWhatsAppLanguage {
+		t.Errorf("unexpected template: %+v", got.Template)
+	}
+	if len(got.Template.Components) != 1 || len(got.Template.Components[0].Parameters) != 2 {
+		t.Fatalf("unexpected components: %+v", got.Template.Components)
+	}
+	if p := got.Template.Components[0].Parameters[1]; p.Type != "text" || p.Text != "ORD-20250101-001" {
+		t.Errorf("unexpected parameter: %+v", p)
+	}
+}
+
+func TestWhatsAppNotifierReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":{"message":"Template name does not exist","type":"OAuthException","code":132001}}`))
+	}))
+	defer server.Close()
+
+	notifier := NewWhatsAppNotifier(WhatsAppConfig{BaseURL: server.URL, PhoneNumberID: "1", AccessToken: "x"})
+	err := notifier.Send(context.Background(), Message{To: "+6281234567890", Template: "missing"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Code != 132001 {
+		t.Errorf("unexpected api error: %+v", apiErr)
+	}
+}
+
+func TestWhatsAppNotifierRejectsEmptyRecipient(t *testing.T) {
+	notifier := NewWhatsAppNotifier(WhatsAppConfig{BaseURL: "http://127.0.0.1:0"})
+	if err := notifier.Send(context.Background(), Message{Template: "order_created"}); err == nil {
+		t.Fatal("expected error for empty recipient")
+	}
+}
@@ -0,0 +1,459 @@
+// Package orders takes customer orders and lets admins work through them.
+package orders
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/repository"
+	"scaff-food-backend/internal/validate"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Hooks connect order changes to stock, notifications and the realtime stream
+type Hooks struct {
+	// Created runs after a new order and its items are stored
+	Created func(order models.Order, items []models.OrderItem)
+	// StatusChanged runs after an admin changed the status of an order.
+	// completionUpdated is set when a completed order got its delivery
+	// photo or appreciation message.
+	StatusChanged func(c *fiber.Ctx, order models.Order, oldStatus, oldPaymentStatus string, completionUpdated bool)
+}
+
+// Handler serves the orders of an OrderRepo
+type Handler struct {
+	repo  repository.OrderRepo
+	hooks Hooks
+}
+
+// NewHandler returns a Handler storing orders in repo and reporting changes
+// to hooks
+func NewHandler(repo repository.OrderRepo, hooks Hooks) *Handler {
+	return &Handler{repo: repo, hooks: hooks}
+}
+
+// Answer with status and message, or 503 while the database is down
+func fail(c *fiber.Ctx, err error, status int, message string) error {
+	if errors.Is(err, repository.ErrUnavailable) {
+		status, message = 503, "Database not connected"
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"success": false,
+		"message": message,
+	})
+}
+
+// Sanitize order data
+func sanitizeOrderData(order *models.Order) {
+	order.CustomerName = validate.SanitizeString(order.CustomerName)
+	order.CustomerEmail = validate.SanitizeString(order.CustomerEmail)
+	order.CustomerPhone = validate.SanitizeString(order.CustomerPhone)
+	order.DeliveryAddress = validate.SanitizeString(order.DeliveryAddress)
+	order.DeliveryLocation = validate.SanitizeString(order.DeliveryLocation)
+	order.PaymentMethod = validate.SanitizeString(order.PaymentMethod)
+	order.CancellationReason = validate.SanitizeString(order.CancellationReason)
+	order.AppreciationMessage = validate.SanitizeString(order.AppreciationMessage)
+	order.Notes = validate.SanitizeString(order.Notes)
+}
+
+// Normalize customer contact fields, call after validateOrderData
+func normalizeOrderContact(order *models.Order) {
+	order.CustomerEmail = validate.NormalizeEmail(order.CustomerEmail)
+	if phone, ok := validate.NormalizePhone(order.CustomerPhone); ok {
+		order.CustomerPhone = phone
+	}
+}
+
+// Validate order data
+func validateOrderData(order *models.Order) error {
+	if order.CustomerName == "" {
+		return fmt.Errorf("customer name is required")
+	}
+	if len(order.CustomerName) > 100 {
+		return fmt.Errorf("customer name is too long")
+	}
+	if len(order.Notes) > 500 {
+		return fmt.Errorf("notes are too long")
+	}
+
+	if !validate.IsValidEmail(order.CustomerEmail) {
+		return fmt.Errorf("invalid email format")
+	}
+
+	if !validate.IsValidPhone(order.CustomerPhone) {
+		return fmt.Errorf("invalid phone number format")
+	}
+
+	if order.DeliveryAddress == "" {
+		return fmt.Errorf("delivery address is required")
+	}
+	if len(order.DeliveryAddress) > 500 {
+		return fmt.Errorf("delivery address is too long")
+	}
+
+	if order.Total <= 0 {
+		return fmt.Errorf("invalid order total")
+	}
+
+	return nil
+}
+
+// CanView reports whether the caller may see an order with its customer
+// details: an admin, the customer who placed it, or whoever holds its
+// tracking token (from the confirmation email).
+func CanView(session *auth.Session, order models.Order, trackingToken string) bool {
+	if session != nil {
+		switch session.Role {
+		case auth.RoleAdmin:
+			return true
+		case auth.RoleCustomer:
+			if order.UserID != nil && *order.UserID == session.UserID {
+				return true
+			}
+			if validate.NormalizeEmail(order.CustomerEmail) == validate.NormalizeEmail(session.Email) {
+				return true
+			}
+		}
+	}
+	return trackingToken != "" && order.TrackingToken != "" &&
+		subtle.ConstantTimeCompare([]byte(trackingToken), []byte(order.TrackingToken)) == 1
+}
+
+// RegisterRoutes mounts order creation, the admin order list and status updates
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	// Create new order
+	app.Post("/api/orders", auth.OptionalSession, func(c *fiber.Ctx) error {
+		var requestData struct {
+			Order models.Order       `json:"order"`
+			Items []models.OrderItem `json:"items"`
+		}
+
+		// Log raw body for debugging
+		bodyBytes := c.Body()
+		log.Printf("📦 Received order request body: %s", string(bodyBytes))
+
+		if err := c.BodyParser(&requestData); err != nil {
+			log.Printf("❌ Error parsing order request: %v", err)
+			log.Printf("❌ Request body: %s", string(c.Body()))
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("Invalid request body: %v", err),
+			})
+		}
+
+		log.Printf("✅ Parsed order data: %+v", requestData.Order)
+		log.Printf("✅ Parsed items: %+v", requestData.Items)
+
+		// Sanitize input data
+		sanitizeOrderData(&requestData.Order)
+
+		// Validate order data
+		if err := validateOrderData(&requestData.Order); err != nil {
+			log.Printf("❌ Order validation failed: %v", err)
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("Validation error: %v", err),
+			})
+		}
+		normalizeOrderContact(&requestData.Order)
+
+		// Validate items
+		if len(requestData.Items) == 0 {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Order must contain at least one item",
+			})
+		}
+
+		// Sanitize item data
+		for i := range requestData.Items {
+			requestData.Items[i].ProductName = validate.SanitizeString(requestData.Items[i].ProductName)
+			requestData.Items[i].VariantName = validate.SanitizeString(requestData.Items[i].VariantName)
+			requestData.Items[i].ConditionName = validate.SanitizeString(requestData.Items[i].ConditionName)
+			for j := range requestData.Items[i].Addons {
+				requestData.Items[i].Addons[j] = validate.SanitizeString(requestData.Items[i].Addons[j])
+			}
+			if requestData.Items[i].VariantID != nil && *requestData.Items[i].VariantID == "" {
+				requestData.Items[i].VariantID = nil
+			}
+		}
+
+		// Generate order number
+		orderNumber := fmt.Sprintf("ORD-%s-%03d", time.Now().Format("20060102"), time.Now().Unix()%1000)
+		requestData.Order.OrderNumber = orderNumber
+		requestData.Order.TrackingToken = auth.GenerateToken(32)
+
+		// Link to the customer account when logged in, guests stay unlinked
+		requestData.Order.UserID = nil
+		if session := auth.Current(c); session != nil && session.Role == auth.RoleCustomer && session.UserID != "" {
+			userID := session.UserID
+			requestData.Order.UserID = &userID
+		}
+		requestData.Order.PaymentStatus = "paid"
+		requestData.Order.OrderStatus = "processing"
+
+		// Create order and its items, nothing is stored if an item fails
+		if err := h.repo.Create(&requestData.Order, requestData.Items); err != nil {
+			log.Printf("Error creating order: %v", err)
+			return fail(c, err, 500, "Failed to create order")
+		}
+
+		log.Printf("✅ Order created: %s for %s", orderNumber, requestData.Order.CustomerEmail)
+
+		if h.hooks.Created != nil {
+			h.hooks.Created(requestData.Order, requestData.Items)
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Order created successfully",
+			"data": fiber.Map{
+				"order": requestData.Order,
+				"items": requestData.Items,
+				// Only returned here, the order JSON leaves it out
+				"tracking_token": requestData.Order.TrackingToken,
+			},
+		})
+	})
+
+	// Get all orders (admin)
+	app.Get("/api/orders", auth.RequireSession, auth.RequireAdmin, func(c *fiber.Ctx) error {
+		// Auto-delete cancelled orders older than 24 hours
+		twentyFourHoursAgo := time.Now().Add(-24 * time.Hour)
+		deleted, err := h.repo.DeleteCancelledBefore(twentyFourHoursAgo)
+		if err != nil {
+			log.Printf("Error auto-deleting old cancelled orders: %v", err)
+		} else if deleted > 0 {
+			log.Printf("🗑️ Auto-deleted %d cancelled orders older than 24 hours", deleted)
+		}
+
+		// Orders with their items
+		ordersWithItems, err := h.repo.List()
+		if err != nil {
+			log.Printf("Error fetching orders: %v", err)
+			return fail(c, err, 500, "Failed to fetch orders")
+		}
+
+		log.Printf("GET /api/orders: Returning %d orders", len(ordersWithItems))
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    ordersWithItems,
+		})
+	})
+
+	// Get pending orders count (public endpoint for badge)
+	app.Get("/api/orders/pending-count", func(c *fiber.Ctx) error {
+		count, err := h.repo.CountByStatus("pending")
+		if err != nil {
+			log.Printf("Error counting pending orders: %v", err)
+			return fail(c, err, 500, "Failed to count pending orders")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"count":   count,
+		})
+	})
+
+	// Get single order by ID (admin, its customer, or ?token=<tracking token>)
+	app.Get("/api/orders/:id", auth.OptionalSession, func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		// Input validation - check UUID format
+		if len(id) != 36 {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid order ID format",
+			})
+		}
+
+		order, err := h.repo.Find(id)
+		if err != nil {
+			return fail(c, err, 404, "Order not found")
+		}
+		if !CanView(auth.Current(c), *order, c.Query("token")) {
+			return c.Status(403).JSON(fiber.Map{
+				"success": false,
+				"message": "Tidak diizinkan melihat pesanan ini",
+			})
+		}
+
+		// Get order items
+		items, err := h.repo.Items(order.ID)
+		if err != nil {
+			log.Printf("Error fetching items of order %s: %v", order.ID, err)
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data": models.OrderWithItems{
+				Order: *order,
+				Items: items,
+			},
+		})
+	})
+
+	// Get orders by customer email or phone (requires customer OTP session)
+	app.Get("/api/orders/customer/:identifier", auth.RequireSession, func(c *fiber.Ctx) error {
+		identifier := strings.TrimSpace(c.Params("identifier"))
+		session := auth.Current(c)
+
+		// A session only ever sees orders placed with its own verified email
+		phone := ""
+		if strings.Contains(identifier, "@") {
+			if validate.NormalizeEmail(identifier) != validate.NormalizeEmail(session.Email) {
+				return c.Status(403).JSON(fiber.Map{
+					"success": false,
+					"message": "Tidak diizinkan melihat pesanan ini",
+				})
+			}
+		} else {
+			normalized, ok := validate.NormalizePhone(identifier)
+			if !ok {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": "Invalid phone number format",
+				})
+			}
+			phone = normalized
+		}
+
+		ordersWithItems, err := h.repo.ListByCustomer(validate.NormalizeEmail(session.Email), phone)
+		if err != nil {
+			log.Printf("Error fetching orders for %s: %v", identifier, err)
+			return fail(c, err, 500, "Failed to fetch orders")
+		}
+
+		log.Printf("GET /api/orders/customer/%s: Returning %d orders", identifier, len(ordersWithItems))
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    ordersWithItems,
+		})
+	})
+
+	// Update order status (admin)
+	app.Put("/api/orders/:id/status", auth.RequireSession, auth.RequireAdmin, func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		var requestData struct {
+			Status              string `json:"status"`
+			PaymentStatus       string `json:"payment_status,omitempty"`
+			CancellationReason  string `json:"cancellation_reason,omitempty"`
+			DeliveryPhoto       string `json:"delivery_photo,omitempty"`
+			AppreciationMessage string `json:"appreciation_message,omitempty"`
+		}
+
+		if err := c.BodyParser(&requestData); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+
+		// Validate cancellation reason if status is dibatalkan or cancelled
+		if models.IsCancelledStatus(requestData.Status) && requestData.CancellationReason == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Alasan pembatalan harus diisi",
+			})
+		}
+
+		order, err := h.repo.Find(id)
+		if err != nil {
+			return fail(c, err, 404, "Order not found")
+		}
+
+		// Only payment_status may be sent on its own
+		if requestData.Status == "" {
+			requestData.Status = order.OrderStatus
+		}
+		if requestData.PaymentStatus != "" && requestData.PaymentStatus != "pending" && requestData.PaymentStatus != "paid" && requestData.PaymentStatus != "failed" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid payment status",
+			})
+		}
+
+		// Update status
+		oldStatus := order.OrderStatus
+		oldPaymentStatus := order.PaymentStatus
+		order.OrderStatus = requestData.Status
+		if requestData.PaymentStatus != "" {
+			order.PaymentStatus = requestData.PaymentStatus
+		}
+
+		// If status is dibatalkan or cancelled, add cancellation reason and timestamp
+		if models.IsCancelledStatus(requestData.Status) {
+			now := time.Now()
+			order.CancellationReason = requestData.CancellationReason
+			order.CancelledAt = &now
+		}
+
+		// If status is completed, add delivery photo and appreciation message if provided
+		if requestData.Status == "completed" {
+			if requestData.DeliveryPhoto != "" {
+				order.DeliveryPhoto = requestData.DeliveryPhoto
+			}
+			if requestData.AppreciationMessage != "" {
+				order.AppreciationMessage = requestData.AppreciationMessage
+			}
+		}
+
+		err = h.repo.SaveStatus(order, oldStatus, oldPaymentStatus)
+		if errors.Is(err, repository.ErrConflict) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Status pesanan sudah diubah. Muat ulang dan coba lagi.",
+			})
+		}
+		if err != nil {
+			log.Printf("Error updating order status: %v", err)
+			return fail(c, err, 500, "Failed to update order status")
+		}
+		log.Printf("✅ Order %s status updated: %s → %s", order.OrderNumber, oldStatus, requestData.Status)
+
+		// Delivery photo and appreciation message can be added after completion
+		completionUpdated := requestData.Status == "completed" && (requestData.DeliveryPhoto != "" || requestData.AppreciationMessage != "")
+		if h.hooks.StatusChanged != nil {
+			h.hooks.StatusChanged(c, *order, oldStatus, oldPaymentStatus, completionUpdated)
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Order status updated",
+			"data":    order,
+		})
+	})
+
+	// Delete order (admin)
+	app.Delete("/api/orders/:id", auth.RequireSession, auth.RequireAdmin, func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		// Get order first for logging
+		order, err := h.repo.Find(id)
+		if err != nil {
+			return fail(c, err, 404, "Order not found")
+		}
+
+		// Delete order (items will be deleted by CASCADE)
+		if err := h.repo.Delete(id); err != nil {
+			log.Printf("Error deleting order: %v", err)
+			return fail(c, err, 500, "Failed to delete order")
+		}
+
+		log.Printf("✅ Order deleted: %s", order.OrderNumber)
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Order deleted successfully",
+		})
+	})
+}
@@ -0,0 +1,380 @@
+package orders
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const orderBody = `{
+	"order": {
+		"customer_name": "Sari",
+		"customer_email": " Sari@Example.com ",
+		"customer_phone": "0812-3456-7890",
+		"delivery_address": "Jl. Merdeka 1",
+		"subtotal": 50000,
+		"total": 50000
+	},
+	"items": [
+		{"product_id": "p1", "product_name": "Nasi Bakar", "product_price": 25000, "quantity": 2, "subtotal": 50000}
+	]
+}`
+
+var adminToken = auth.Sessions.Create("admin-1", "admin@example.com", auth.RoleAdmin, time.Hour)
+
+func newApp(repo repository.OrderRepo, hooks Hooks) *fiber.App {
+	app := fiber.New()
+	NewHandler(repo, hooks).RegisterRoutes(app)
+	return app
+}
+
+func do(t *testing.T, app *fiber.App, method, target, token, body string) (int, map[string]interface{}) {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, target, err)
+	}
+	var payload map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("%s %s: decode body: %v", method, target, err)
+	}
+	return resp.StatusCode, payload
+}
+
+// Store an order directly, bypassing the handler
+func seedOrder(t *testing.T, repo *repository.MemoryOrderRepo, email, phone, status string) models.Order {
+	t.Helper()
+	order := models.Order{
+		OrderNumber:   "ORD-" + email + "-" + phone,
+		CustomerName:  "Customer",
+		CustomerEmail: email,
+		CustomerPhone: phone,
+		Total:         10000,
+		PaymentStatus: "paid",
+		OrderStatus:   status,
+	}
+	items := []models.OrderItem{{ProductName: "Nasi Bakar", Quantity: 1, Subtotal: 10000}}
+	if err := repo.Create(&order, items); err != nil {
+		t.Fatal(err)
+	}
+	return order
+}
+
+func TestCreateOrder(t *testing.T) {
+	repo := repository.NewMemoryOrderRepo()
+	var created models.Order
+	var createdItems []models.OrderItem
+	app := newApp(repo, Hooks{
+		Created: func(order models.Order, items []models.OrderItem) {
+			created, createdItems = order, items
+		},
+	})
+
+	status, payload := do(t, app, "POST", "/api/orders", "", orderBody)
+	if status != 200 {
+		t.Fatalf("status = %d, body = %v", status, payload)
+	}
+
+	if created.ID == "" || created.TrackingToken == "" || !strings.HasPrefix(created.OrderNumber, "ORD-") {
+		t.Errorf("created order = %+v", created)
+	}
+	if created.CustomerEmail != "sari@example.com" || created.CustomerPhone != "+6281234567890" {
+		t.Errorf("contact = %q %q, want normalized", created.CustomerEmail, created.CustomerPhone)
+	}
+	if created.UserID != nil {
+		t.Errorf("guest order linked to user %v", *created.UserID)
+	}
+	if len(createdItems) != 1 || createdItems[0].OrderID != created.ID {
+		t.Errorf("items = %+v", createdItems)
+	}
+
+	stored, err := repo.Items(created.ID)
+	if err != nil || len(stored) != 1 {
+		t.Errorf("stored items = %v, %v", stored, err)
+	}
+
+	// The tracking token is only handed out once, next to the order
+	data := payload["data"].(map[string]interface{})
+	if data["tracking_token"] != created.TrackingToken {
+		t.Errorf("tracking_token = %v, want %s", data["tracking_token"], created.TrackingToken)
+	}
+	if _, ok := data["order"].(map[string]interface{})["tracking_token"]; ok {
+		t.Error("order JSON includes the tracking token")
+	}
+}
+
+func TestCreateOrderLinksCustomerSession(t *testing.T) {
+	repo := repository.NewMemoryOrderRepo()
+	app := newApp(repo, Hooks{})
+	token := auth.Sessions.Create("user-1", "sari@example.com", auth.RoleCustomer, time.Hour)
+
+	status, payload := do(t, app, "POST", "/api/orders", token, orderBody)
+	if status != 200 {
+		t.Fatalf("status = %d, body = %v", status, payload)
+	}
+	order := payload["data"].(map[string]interface{})["order"].(map[string]interface{})
+	if order["user_id"] != "user-1" {
+		t.Errorf("user_id = %v, want user-1", order["user_id"])
+	}
+}
+
+func TestCreateOrderValidation(t *testing.T) {
+	app := newApp(repository.NewMemoryOrderRepo(), Hooks{})
+
+	cases := map[string]string{
+		"invalid email": strings.Replace(orderBody, " Sari@Example.com ", "not-an-email", 1),
+		"invalid phone": strings.Replace(orderBody, "0812-3456-7890", "12345", 1),
+		"no items":      `{"order":{"customer_name":"Sari","customer_email":"sari@example.com","customer_phone":"081234567890","delivery_address":"Jl. Merdeka 1","total":1000},"items":[]}`,
+	}
+	for name, body := range cases {
+		if status, _ := do(t, app, "POST", "/api/orders", "", body); status != 400 {
+			t.Errorf("%s: status = %d, want 400", name, status)
+		}
+	}
+}
+
+func TestListOrdersPurgesOldCancelled(t *testing.T) {
+	repo := repository.NewMemoryOrderRepo()
+	seedOrder(t, repo, "a@example.com", "+6281111111111", "processing")
+	cancelled := seedOrder(t, repo, "b@example.com", "+6282222222222", "cancelled")
+	longAgo := time.Now().Add(-48 * time.Hour)
+	cancelled.CancelledAt = &longAgo
+	repo.SaveStatus(&cancelled, "cancelled", "paid")
+	app := newApp(repo, Hooks{})
+
+	status, payload := do(t, app, "GET", "/api/orders", adminToken, "")
+	if status != 200 {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	orders := payload["data"].([]interface{})
+	if len(orders) != 1 {
+		t.Fatalf("orders = %d, want 1 after purging the old cancelled order", len(orders))
+	}
+	if got := len(orders[0].(map[string]interface{})["items"].([]interface{})); got != 1 {
+		t.Errorf("items = %d, want 1", got)
+	}
+}
+
+func TestAdminRoutesNeedAdminSession(t *testing.T) {
+	repo := repository.NewMemoryOrderRepo()
+	order := seedOrder(t, repo, "a@example.com", "+6281111111111", "pending")
+	app := newApp(repo, Hooks{})
+	customer := auth.Sessions.Create("user-1", "a@example.com", auth.RoleCustomer, time.Hour)
+
+	routes := []struct{ method, target, body string }{
+		{"GET", "/api/orders", ""},
+		{"PUT", "/api/orders/" + order.ID + "/status", `{"status":"completed"}`},
+		{"DELETE", "/api/orders/" + order.ID, ""},
+	}
+	for _, route := range routes {
+		if status, _ := do(t, app, route.method, route.target, "", route.body); status != 401 {
+			t.Errorf("%s %s without session: status = %d, want 401", route.method, route.target, status)
+		}
+		if status, _ := do(t, app, route.method, route.target, customer, route.body); status != 403 {
+			t.Errorf("%s %s as customer: status = %d, want 403", route.method, route.target, status)
+		}
+	}
+}
+
+func TestPendingCount(t *testing.T) {
+	repo := repository.NewMemoryOrderRepo()
+	seedOrder(t, repo, "a@example.com", "+6281111111111", "pending")
+	seedOrder(t, repo, "b@example.com", "+6282222222222", "processing")
+	app := newApp(repo, Hooks{})
+
+	_, payload := do(t, app, "GET", "/api/orders/pending-count", "", "")
+	if payload["count"] != float64(1) {
+		t.Errorf("count = %v, want 1", payload["count"])
+	}
+}
+
+func TestGetOrder(t *testing.T) {
+	repo := repository.NewMemoryOrderRepo()
+	order := models.Order{
+		OrderNumber:   "ORD-1",
+		CustomerName:  "Customer",
+		CustomerEmail: "a@example.com",
+		CustomerPhone: "+6281111111111",
+		Total:         10000,
+		OrderStatus:   "pending",
+		TrackingToken: "secret-token",
+	}
+	if err := repo.Create(&order, nil); err != nil {
+		t.Fatal(err)
+	}
+	app := newApp(repo, Hooks{})
+
+	status, payload := do(t, app, "GET", "/api/orders/"+order.ID, adminToken, "")
+	if status != 200 {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if payload["data"].(map[string]interface{})["order_number"] != order.OrderNumber {
+		t.Errorf("data = %v", payload["data"])
+	}
+
+	owner := auth.Sessions.Create("user-1", "A@example.com", auth.RoleCustomer, time.Hour)
+	other := auth.Sessions.Create("user-2", "b@example.com", auth.RoleCustomer, time.Hour)
+	access := []struct {
+		name, target, token string
+		want                int
+	}{
+		{"anonymous", "/api/orders/" + order.ID, "", 403},
+		{"other customer", "/api/orders/" + order.ID, other, 403},
+		{"wrong tracking token", "/api/orders/" + order.ID + "?token=guess", "", 403},
+		{"customer who ordered", "/api/orders/" + order.ID, owner, 200},
+		{"tracking token", "/api/orders/" + order.ID + "?token=secret-token", "", 200},
+	}
+	for _, test := range access {
+		if status, _ := do(t, app, "GET", test.target, test.token, ""); status != test.want {
+			t.Errorf("%s: status = %d, want %d", test.name, status, test.want)
+		}
+	}
+
+	if status, _ := do(t, app, "GET", "/api/orders/short", "", ""); status != 400 {
+		t.Errorf("malformed id: status = %d, want 400", status)
+	}
+	if status, _ := do(t, app, "GET", "/api/orders/00000000-0000-4000-8000-000000000000", "", ""); status != 404 {
+		t.Errorf("unknown id: status = %d, want 404", status)
+	}
+}
+
+func TestCustomerOrdersAreScopedToSession(t *testing.T) {
+	repo := repository.NewMemoryOrderRepo()
+	seedOrder(t, repo, "sari@example.com", "+6281234567890", "pending")
+	seedOrder(t, repo, "sari@example.com", "+6289999999999", "pending")
+	seedOrder(t, repo, "budi@example.com", "+6281234567890", "pending")
+	app := newApp(repo, Hooks{})
+	token := auth.Sessions.Create("user-1", "sari@example.com", auth.RoleCustomer, time.Hour)
+
+	if status, _ := do(t, app, "GET", "/api/orders/customer/sari@example.com", "", ""); status != 401 {
+		t.Errorf("without session: status = %d, want 401", status)
+	}
+	if status, _ := do(t, app, "GET", "/api/orders/customer/budi@example.com", token, ""); status != 403 {
+		t.Errorf("other email: status = %d, want 403", status)
+	}
+
+	_, payload := do(t, app, "GET", "/api/orders/customer/sari@example.com", token, "")
+	if got := len(payload["data"].([]interface{})); got != 2 {
+		t.Errorf("by email = %d orders, want 2", got)
+	}
+
+	// A phone lookup still only sees the session's own orders
+	_, payload = do(t, app, "GET", "/api/orders/customer/081234567890", token, "")
+	if got := len(payload["data"].([]interface{})); got != 1 {
+		t.Errorf("by phone = %d orders, want 1", got)
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	repo := repository.NewMemoryOrderRepo()
+	order := seedOrder(t, repo, "a@example.com", "+6281111111111", "processing")
+
+	var gotOld, gotNew string
+	var gotCompletion bool
+	app := newApp(repo, Hooks{
+		StatusChanged: func(c *fiber.Ctx, order models.Order, oldStatus, oldPaymentStatus string, completionUpdated bool) {
+			gotOld, gotNew, gotCompletion = oldStatus, order.OrderStatus, completionUpdated
+		},
+	})
+
+	status, payload := do(t, app, "PUT", "/api/orders/"+order.ID+"/status", adminToken,
+		`{"status":"completed","delivery_photo":"/produk/proof.jpg"}`)
+	if status != 200 {
+		t.Fatalf("status = %d, body = %v", status, payload)
+	}
+	if gotOld != "processing" || gotNew != "completed" || !gotCompletion {
+		t.Errorf("hook got %s -> %s completion %v", gotOld, gotNew, gotCompletion)
+	}
+
+	stored, _ := repo.Find(order.ID)
+	if stored.OrderStatus != "completed" || stored.DeliveryPhoto != "/produk/proof.jpg" {
+		t.Errorf("stored order = %+v", stored)
+	}
+}
+
+// Answers Find with the order as it was before, like a request that read it
+// just before another one saved a change
+type staleOrderRepo struct {
+	*repository.MemoryOrderRepo
+	snapshot models.Order
+}
+
+func (r *staleOrderRepo) Find(id string) (*models.Order, error) {
+	order := r.snapshot
+	return &order, nil
+}
+
+func TestConcurrentCancelAppliesOnce(t *testing.T) {
+	memory := repository.NewMemoryOrderRepo()
+	order := seedOrder(t, memory, "a@example.com", "+6281111111111", "processing")
+	repo := &staleOrderRepo{MemoryOrderRepo: memory, snapshot: order}
+
+	hookCalls := 0
+	app := newApp(repo, Hooks{
+		StatusChanged: func(c *fiber.Ctx, order models.Order, oldStatus, oldPaymentStatus string, completionUpdated bool) {
+			hookCalls++
+		},
+	})
+
+	body := `{"status":"cancelled","cancellation_reason":"Stok habis"}`
+	if status, _ := do(t, app, "PUT", "/api/orders/"+order.ID+"/status", adminToken, body); status != 200 {
+		t.Fatalf("first cancel: status = %d, want 200", status)
+	}
+	if status, _ := do(t, app, "PUT", "/api/orders/"+order.ID+"/status", adminToken, body); status != 409 {
+		t.Errorf("second cancel: status = %d, want 409", status)
+	}
+	if hookCalls != 1 {
+		t.Errorf("hook ran %d times, want once", hookCalls)
+	}
+}
+
+func TestCancelRequiresReason(t *testing.T) {
+	repo := repository.NewMemoryOrderRepo()
+	order := seedOrder(t, repo, "a@example.com", "+6281111111111", "processing")
+	app := newApp(repo, Hooks{})
+
+	if status, _ := do(t, app, "PUT", "/api/orders/"+order.ID+"/status", adminToken, `{"status":"cancelled"}`); status != 400 {
+		t.Errorf("without reason: status = %d, want 400", status)
+	}
+
+	status, _ := do(t, app, "PUT", "/api/orders/"+order.ID+"/status", adminToken, `{"status":"cancelled","cancellation_reason":"Stok habis"}`)
+	if status != 200 {
+		t.Fatalf("with reason: status = %d, want 200", status)
+	}
+	stored, _ := repo.Find(order.ID)
+	if stored.CancellationReason != "Stok habis" || stored.CancelledAt == nil {
+		t.Errorf("stored order = %+v", stored)
+	}
+}
+
+func TestDeleteOrder(t *testing.T) {
+	repo := repository.NewMemoryOrderRepo()
+	order := seedOrder(t, repo, "a@example.com", "+6281111111111", "pending")
+	app := newApp(repo, Hooks{})
+
+	if status, _ := do(t, app, "DELETE", "/api/orders/"+order.ID, adminToken, ""); status != 200 {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if _, err := repo.Find(order.ID); err != repository.ErrNotFound {
+		t.Errorf("Find after delete = %v, want ErrNotFound", err)
+	}
+}
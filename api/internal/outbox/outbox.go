@@ -0,0 +1,398 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Message statuses
+const (
+	StatusPending    = "pending"
+	StatusProcessing = "processing"
+	StatusSent       = "sent"
+	StatusDead       = "dead"
+)
+
+// ErrNoDatabase is returned while the database is not connected
+var ErrNoDatabase = errors.New("outbox: database not connected")
+
+// Message is one queued email or notification
+type Message struct {
+	ID            string     `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Channel       string     `gorm:"not null" json:"channel"`
+	Recipient     string     `gorm:"not null" json:"recipient"`
+	Payload       string     `gorm:"type:jsonb;not null" json:"payload"`
+	Status        string     `gorm:"default:'pending'" json:"status"`
+	Attempts      int        `gorm:"default:0" json:"attempts"`
+	MaxAttempts   int        `gorm:"default:5" json:"max_attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	LockedAt      *time.Time `json:"locked_at,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (Message) TableName() string {
+	return "outbox_messages"
+}
+
+// Handler delivers one message, returning an error schedules a retry
+type Handler func(ctx context.Context, msg Message) error
+
+// Config tunes the worker pool
+type Config struct {
+	Workers      int
+	PollInterval time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	SendTimeout  time.Duration
+	// Messages stuck in processing longer than this are picked up again
+	StaleAfter time.Duration
+}
+
+// Outbox is a Postgres-backed queue with a pool of delivery workers
+type Outbox struct {
+	db       func() *gorm.DB
+	config   Config
+	handlers map[string]Handler
+	wake     chan struct{}
+	mu       sync.RWMutex
+}
+
+// New creates an outbox, db is called on every use so it may return nil
+// while the database is down
+func New(db func() *gorm.DB, config Config) *Outbox {
+	if config.Workers <= 0 {
+		config.Workers = 4
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = 2 * time.Second
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.BaseBackoff <= 0 {
+		config.BaseBackoff = 30 * time.Second
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = time.Hour
+	}
+	if config.SendTimeout <= 0 {
+		config.SendTimeout = 30 * time.Second
+	}
+	if config.StaleAfter <= 0 {
+		config.StaleAfter = 5 * time.Minute
+	}
+	return &Outbox{
+		db:       db,
+		config:   config,
+		handlers: make(map[string]Handler),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Handle registers the handler for a channel
+func (o *Outbox) Handle(channel string, handler Handler) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.handlers[channel] = handler
+}
+
+func (o *Outbox) handler(channel string) (Handler, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	h, ok := o.handlers[channel]
+	return h, ok
+}
+
+// Enqueue stores a message for delivery, payload is encoded as JSON
+func (o *Outbox) Enqueue(channel, recipient string, payload interface{}) (*Message, error) {
+	db := o.db()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("outbox: encode payload: %w", err)
+	}
+
+	msg := Message{
+		Channel:       channel,
+		Recipient:     recipient,
+		Payload:       string(encoded),
+		Status:        StatusPending,
+		MaxAttempts:   o.config.MaxAttempts,
+		NextAttemptAt: time.Now(),
+	}
+	if err := db.Create(&msg).Error; err != nil {
+		return nil, err
+	}
+
+	o.notify()
+	return &msg, nil
+}
+
+// Get returns one message by id
+func (o *Outbox) Get(id string) (*Message, error) {
+	db := o.db()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	var msg Message
+	if err := db.First(&msg, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &msg, nil
+}
+
+// Retry puts a dead or pending message back in the queue with fresh attempts
+func (o *Outbox) Retry(id string) (*Message, error) {
+	db := o.db()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	result := db.Model(&Message{}).
+		Where("id = ? AND status IN ?", id, []string{StatusDead, StatusPending}).
+		Updates(map[string]interface{}{
+			"status":          StatusPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+			"locked_at":       nil,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var msg Message
+	if err := db.First(&msg, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	o.notify()
+	return &msg, nil
+}
+
+// List messages, newest first, optionally filtered by status
+func (o *Outbox) List(status string, limit int) ([]Message, error) {
+	db := o.db()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	query := db.Order("created_at DESC").Limit(limit)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var messages []Message
+	err := query.Find(&messages).Error
+	return messages, err
+}
+
+// ListFor returns the messages of one channel and recipient, newest first
+func (o *Outbox) ListFor(channel, recipient string, limit int) ([]Message, error) {
+	db := o.db()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	var messages []Message
+	err := db.Where("channel = ? AND recipient = ?", channel, recipient).
+		Order("created_at DESC").Limit(limit).Find(&messages).Error
+	return messages, err
+}
+
+// Counts returns the number of messages per status
+func (o *Outbox) Counts() (map[string]int64, error) {
+	db := o.db()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := db.Model(&Message{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int64{StatusPending: 0, StatusProcessing: 0, StatusSent: 0, StatusDead: 0}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// Wake the workers without blocking
+func (o *Outbox) notify() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Backoff before the given attempt number: base * 2^(attempt-1), capped
+func (o *Outbox) backoff(attempt int) time.Duration {
+	d := float64(o.config.BaseBackoff) * math.Pow(2, float64(attempt-1))
+	if d > float64(o.config.MaxBackoff) {
+		return o.config.MaxBackoff
+	}
+	return time.Duration(d)
+}
+
+// Start runs the worker pool until ctx is cancelled
+func (o *Outbox) Start(ctx context.Context) {
+	for i := 0; i < o.config.Workers; i++ {
+		go o.work(ctx)
+	}
+	go o.recoverStale(ctx)
+	log.Printf("📬 Outbox started with %d workers", o.config.Workers)
+}
+
+func (o *Outbox) work(ctx context.Context) {
+	ticker := time.NewTicker(o.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain everything that is due before waiting again
+		for {
+			msg, err := o.claim()
+			if err != nil {
+				if err != ErrNoDatabase {
+					log.Printf("❌ Outbox claim failed: %v", err)
+				}
+				break
+			}
+			if msg == nil {
+				break
+			}
+			o.deliver(ctx, *msg)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-o.wake:
+		}
+	}
+}
+
+// Claim one due message, SKIP LOCKED keeps workers on different replicas apart
+func (o *Outbox) claim() (*Message, error) {
+	db := o.db()
+	if db == nil {
+		return nil, ErrNoDatabase
+	}
+
+	var messages []Message
+	err := db.Raw(`
+		UPDATE outbox_messages SET
+			status = ?,
+			attempts = attempts + 1,
+			locked_at = NOW(),
+			updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM outbox_messages
+			WHERE status = ? AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *
+	`, StatusProcessing, StatusPending).Scan(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(messages) == 0 {
+		return nil, nil
+	}
+	return &messages[0], nil
+}
+
+func (o *Outbox) deliver(ctx context.Context, msg Message) {
+	err := fmt.Errorf("no handler for channel %q", msg.Channel)
+	if handler, ok := o.handler(msg.Channel); ok {
+		sendCtx, cancel := context.WithTimeout(ctx, o.config.SendTimeout)
+		err = handler(sendCtx, msg)
+		cancel()
+	}
+
+	db := o.db()
+	if db == nil {
+		// Left in processing, recoverStale picks it up again
+		return
+	}
+
+	now := time.Now()
+	if err == nil {
+		db.Model(&Message{}).Where("id = ?", msg.ID).Updates(map[string]interface{}{
+			"status":     StatusSent,
+			"sent_at":    now,
+			"locked_at":  nil,
+			"last_error": "",
+		})
+		log.Printf("📤 Outbox %s message %s sent to %s", msg.Channel, msg.ID, msg.Recipient)
+		return
+	}
+
+	updates := map[string]interface{}{
+		"status":     StatusPending,
+		"locked_at":  nil,
+		"last_error": err.Error(),
+	}
+	if msg.Attempts >= msg.MaxAttempts {
+		updates["status"] = StatusDead
+		log.Printf("☠️ Outbox %s message %s dead after %d attempts: %v", msg.Channel, msg.ID, msg.Attempts, err)
+	} else {
+		updates["next_attempt_at"] = now.Add(o.backoff(msg.Attempts))
+		log.Printf("⚠️ Outbox %s message %s attempt %d failed, retrying: %v", msg.Channel, msg.ID, msg.Attempts, err)
+	}
+	db.Model(&Message{}).Where("id = ?", msg.ID).Updates(updates)
+}
+
+// Put messages abandoned in processing (crash, DB outage) back in the queue
+func (o *Outbox) recoverStale(ctx context.Context) {
+	ticker := time.NewTicker(o.config.StaleAfter)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		db := o.db()
+		if db == nil {
+			continue
+		}
+		result := db.Model(&Message{}).
+			Where("status = ? AND locked_at < ?", StatusProcessing, time.Now().Add(-o.config.StaleAfter)).
+			Updates(map[string]interface{}{
+				"status":          StatusPending,
+				"locked_at":       nil,
+				"next_attempt_at": time.Now(),
+			})
+		if result.RowsAffected > 0 {
+			log.Printf("🧹 Outbox recovered %d stale messages", result.RowsAffected)
+		}
+	}
+}
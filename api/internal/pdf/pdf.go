@@ -0,0 +1,259 @@
+// Package pdf writes simple text documents (prep sheets, invoices) as PDF
+// using the standard Helvetica fonts, so no font files are embedded.
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A4 page size in points
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Document is a PDF being built page by page
+type Document struct {
+	Width  float64
+	Height float64
+	pages  []*Page
+}
+
+// Page collects the drawing operators of one page. Coordinates are in
+// points from the top left corner.
+type Page struct {
+	doc     *Document
+	content bytes.Buffer
+}
+
+// New creates an empty A4 portrait document
+func New() *Document {
+	return &Document{Width: A4Width, Height: A4Height}
+}
+
+// AddPage starts a new page and returns it
+func (d *Document) AddPage() *Page {
+	p := &Page{doc: d}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// PageCount returns the number of pages added so far
+func (d *Document) PageCount() int {
+	return len(d.pages)
+}
+
+func fontName(bold bool) string {
+	if bold {
+		return "F2"
+	}
+	return "F1"
+}
+
+// Text draws s with its baseline at y
+func (p *Page) Text(x, y, size float64, bold bool, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n",
+		fontName(bold), size, x, p.doc.Height-y, escape(encode(s)))
+}
+
+// TextRight draws s so that it ends at x, for amounts in columns
+func (p *Page) TextRight(x, y, size float64, bold bool, s string) {
+	p.Text(x-TextWidth(s, size, bold), y, size, bold, s)
+}
+
+// TextCenter draws s centered on x
+func (p *Page) TextCenter(x, y, size float64, bold bool, s string) {
+	p.Text(x-TextWidth(s, size, bold)/2, y, size, bold, s)
+}
+
+// Line draws a line of the given width
+func (p *Page) Line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n",
+		width, x1, p.doc.Height-y1, x2, p.doc.Height-y2)
+}
+
+// FillRect fills a rectangle with a gray level, 0 is black and 1 white
+func (p *Page) FillRect(x, y, w, h, gray float64) {
+	fmt.Fprintf(&p.content, "q %.2f g %.2f %.2f %.2f %.2f re f Q\n",
+		gray, x, p.doc.Height-y-h, w, h)
+}
+
+// FillRectRGB fills a rectangle with a color, components from 0 to 1
+func (p *Page) FillRectRGB(x, y, w, h, r, g, b float64) {
+	fmt.Fprintf(&p.content, "q %.3f %.3f %.3f rg %.2f %.2f %.2f %.2f re f Q\n",
+		r, g, b, x, p.doc.Height-y-h, w, h)
+}
+
+// StrokeRect draws the outline of a rectangle
+func (p *Page) StrokeRect(x, y, w, h, width float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f %.2f %.2f re S\n",
+		width, x, p.doc.Height-y-h, w, h)
+}
+
+// TextWidth returns the width of s in points
+func TextWidth(s string, size float64, bold bool) float64 {
+	widths := &helvetica
+	if bold {
+		widths = &helveticaBold
+	}
+	total := 0
+	for _, b := range encode(s) {
+		if b >= 32 && b <= 126 {
+			total += widths[b-32]
+		} else {
+			total += 556
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Fit shortens s with an ellipsis so it is at most maxWidth wide
+func Fit(s string, size float64, bold bool, maxWidth float64) string {
+	if TextWidth(s, size, bold) <= maxWidth {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimSpace(string(runes)) + "..."
+		if TextWidth(candidate, size, bold) <= maxWidth {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// Wrap splits s into lines at most maxWidth wide, breaking on spaces
+func Wrap(s string, size float64, bold bool, maxWidth float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if line != "" && TextWidth(candidate, size, bold) > maxWidth {
+				lines = append(lines, line)
+				candidate = word
+			}
+			line = candidate
+		}
+		lines = append(lines, Fit(line, size, bold, maxWidth))
+	}
+	return lines
+}
+
+// Encode text as WinAnsi (Latin-1 for the characters we use), anything
+// the standard fonts cannot show, like emoji, becomes '?'
+func encode(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			out = append(out, ' ')
+		case r >= 32 && r <= 126, r >= 160 && r <= 255:
+			out = append(out, byte(r))
+		case r == '–' || r == '—':
+			out = append(out, '-')
+		case r == '‘' || r == '’':
+			out = append(out, '\'')
+		case r == '“' || r == '”':
+			out = append(out, '"')
+		case r == '•':
+			out = append(out, 149)
+		case r == '\uFE0F' || r == '\u200D':
+			// emoji variation selectors and joiners
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
+
+func escape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch c {
+		case '(', ')', '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// WriteTo writes the finished document
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var buf bytes.Buffer
+	var offsets []int
+
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = []*Page{{doc: d}}
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1 catalog, 2 page tree, 3 and 4 fonts, then a page and its content per page
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+
+	for i, p := range pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			d.Width, d.Height, 6+i*2))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return buf.WriteTo(w)
+}
+
+// Bytes returns the finished document
+func (d *Document) Bytes() []byte {
+	var buf bytes.Buffer
+	d.WriteTo(&buf)
+	return buf.Bytes()
+}
+
+// Glyph widths of characters 32-126 in 1/1000 em, from the standard AFM files
+var helvetica = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBold = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
@@ -0,0 +1,426 @@
+// Package products serves the product catalogue and its admin management.
+package products
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Hooks connect product changes to the inventory ledger
+type Hooks struct {
+	// Created runs after a product and its variants are stored
+	Created func(c *fiber.Ctx, product models.Product, variants []models.ProductVariant)
+	// Updated runs after the product row is saved. product.Stock holds the
+	// requested stock, oldStock what was stored before; variants are the
+	// variants sent with the form.
+	Updated func(c *fiber.Ctx, product models.Product, oldStock int, variants []models.ProductVariant)
+}
+
+// Trim a SKU, blank means none
+func normalizeSKU(sku *string) *string {
+	if sku == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*sku)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
+
+// Handler serves the products of a ProductRepo
+type Handler struct {
+	repo  repository.ProductRepo
+	hooks Hooks
+}
+
+// NewHandler returns a Handler storing products in repo and reporting
+// changes to hooks
+func NewHandler(repo repository.ProductRepo, hooks Hooks) *Handler {
+	return &Handler{repo: repo, hooks: hooks}
+}
+
+// Answer with status and message, or 503 while the database is down
+func fail(c *fiber.Ctx, err error, status int, message string) error {
+	if errors.Is(err, repository.ErrUnavailable) {
+		status, message = 503, "Database not connected"
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"success": false,
+		"message": message,
+	})
+}
+
+// RegisterRoutes mounts the public catalogue and the admin product management
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	// Test endpoint to check product columns
+	app.Get("/api/test/product-columns", func(c *fiber.Ctx) error {
+		product, err := h.repo.First()
+		if err != nil {
+			return c.JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(fiber.Map{
+			"name":                   product.Name,
+			"min_order":              product.MinOrder,
+			"min_order_tb":           product.MinOrderTB,
+			"min_order_luar_tb":      product.MinOrderLuarTB,
+			"available_days_tb":      product.AvailableDaysTB,
+			"available_days_luar_tb": product.AvailableDaysLuarTB,
+		})
+	})
+
+	// Product endpoints. The public menu passes include_sold_out=true to show
+	// unavailable products as sold out, the admin list needs a session.
+	app.Get("/api/products", func(c *fiber.Ctx) error {
+		list := h.repo.ListAvailable
+		if c.QueryBool("include_sold_out") {
+			list = h.repo.List
+		}
+		products, err := list()
+		if err != nil {
+			log.Printf("Error fetching products: %v", err)
+			return fail(c, err, 500, "Failed to fetch products")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    products,
+		})
+	})
+
+	app.Get("/api/products/:id", func(c *fiber.Ctx) error {
+		product, err := h.repo.FindAvailable(c.Params("id"))
+		if err != nil {
+			return fail(c, err, 404, "Product not found")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    product,
+		})
+	})
+
+	// Admin: Get all products (including unavailable)
+	app.Get("/api/admin/products", func(c *fiber.Ctx) error {
+		products, err := h.repo.List()
+		if err != nil {
+			log.Printf("Error fetching products: %v", err)
+			return fail(c, err, 500, "Failed to fetch products")
+		}
+
+		// Log availability status
+		availableCount := 0
+		unavailableCount := 0
+		for _, p := range products {
+			if p.IsAvailable {
+				availableCount++
+			} else {
+				unavailableCount++
+			}
+		}
+		log.Printf("GET /api/admin/products: Returning %d products (available: %d, unavailable: %d)",
+			len(products), availableCount, unavailableCount)
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    products,
+		})
+	})
+
+	// Admin: Create product
+	app.Post("/api/admin/products", func(c *fiber.Ctx) error {
+		var requestData struct {
+			models.Product
+			Variants   []models.ProductVariant  `json:"variants"`
+			Conditions []map[string]interface{} `json:"conditions"`
+			Addons     []map[string]interface{} `json:"addons"`
+		}
+
+		if err := c.BodyParser(&requestData); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+
+		requestData.SKU = normalizeSKU(requestData.SKU)
+		if requestData.SKU != nil {
+			taken, err := h.repo.SKUTaken(*requestData.SKU, "")
+			if err != nil {
+				return fail(c, err, 500, "Failed to create product")
+			}
+			if taken {
+				return c.Status(409).JSON(fiber.Map{
+					"success": false,
+					"message": "SKU is already used by another product",
+				})
+			}
+		}
+
+		// Convert conditions array to JSON string
+		if len(requestData.Conditions) > 0 {
+			conditionsJSON, err := json.Marshal(requestData.Conditions)
+			if err == nil {
+				requestData.Product.Conditions = string(conditionsJSON)
+			}
+		} else {
+			requestData.Product.Conditions = "[]"
+		}
+
+		// Convert addons array to JSON string
+		if len(requestData.Addons) > 0 {
+			addonsJSON, err := json.Marshal(requestData.Addons)
+			if err == nil {
+				requestData.Product.Addons = string(addonsJSON)
+			}
+		} else {
+			requestData.Product.Addons = "[]"
+		}
+
+		// Set default values
+		if requestData.Stock == 0 {
+			requestData.Stock = 100
+		}
+		// Always set new products as available
+		requestData.IsAvailable = true
+		if requestData.MinOrder == 0 {
+			requestData.MinOrder = 1
+		}
+
+		// Create product
+		product := requestData.Product
+		if err := h.repo.Create(&product); err != nil {
+			log.Printf("Error creating product: %v", err)
+			return fail(c, err, 500, "Failed to create product")
+		}
+
+		// Create variants if provided
+		if len(requestData.Variants) > 0 {
+			for i := range requestData.Variants {
+				requestData.Variants[i].ProductID = product.ID
+				if requestData.Variants[i].Stock == 0 {
+					requestData.Variants[i].Stock = 100
+				}
+				requestData.Variants[i].IsAvailable = true
+			}
+
+			if err := h.repo.CreateVariants(requestData.Variants); err != nil {
+				log.Printf("Error creating variants: %v", err)
+				// Don't fail the whole request, just log the error
+			}
+		}
+
+		// Start the inventory ledger from the initial stock
+		if h.hooks.Created != nil {
+			h.hooks.Created(c, product, requestData.Variants)
+		}
+
+		// Reload product with variants
+		if reloaded, err := h.repo.Find(product.ID); err == nil {
+			product = *reloaded
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    product,
+			"message": "Product created successfully",
+		})
+	})
+
+	// Admin: Update product
+	app.Put("/api/admin/products/:id", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		// Find existing product
+		product, err := h.repo.Find(id)
+		if err != nil {
+			return fail(c, err, 404, "Product not found")
+		}
+
+		// Parse update data including variants
+		var requestData struct {
+			models.Product
+			Variants   []models.ProductVariant  `json:"variants"`
+			Conditions []map[string]interface{} `json:"conditions"`
+			Addons     []map[string]interface{} `json:"addons"`
+			// Optional, the current threshold is kept when omitted
+			LowStockThreshold *int `json:"low_stock_threshold"`
+		}
+
+		// Get raw body for debugging
+		bodyBytes := c.Body()
+		log.Printf("📦 Raw request body: %s", string(bodyBytes))
+
+		if err := c.BodyParser(&requestData); err != nil {
+			log.Printf("❌ Error parsing body: %v", err)
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+
+		log.Printf("📦 Update product request data:")
+		log.Printf("  - MinOrderTB: %d", requestData.MinOrderTB)
+		log.Printf("  - MinOrderLuarTB: %d", requestData.MinOrderLuarTB)
+		log.Printf("  - AvailableDaysTB: %v", requestData.AvailableDaysTB)
+		log.Printf("  - AvailableDaysLuarTB: %v", requestData.AvailableDaysLuarTB)
+
+		// SKU only changes when sent, an empty string clears it
+		skuSent := requestData.SKU != nil
+		requestData.SKU = normalizeSKU(requestData.SKU)
+		if requestData.SKU != nil {
+			taken, err := h.repo.SKUTaken(*requestData.SKU, id)
+			if err != nil {
+				return fail(c, err, 500, "Failed to update product")
+			}
+			if taken {
+				return c.Status(409).JSON(fiber.Map{
+					"success": false,
+					"message": "SKU is already used by another product",
+				})
+			}
+		}
+
+		// Convert conditions array to JSON string
+		if len(requestData.Conditions) > 0 {
+			conditionsJSON, err := json.Marshal(requestData.Conditions)
+			if err == nil {
+				requestData.Product.Conditions = string(conditionsJSON)
+			}
+		} else {
+			requestData.Product.Conditions = "[]"
+		}
+
+		// Convert addons array to JSON string
+		if len(requestData.Addons) > 0 {
+			addonsJSON, err := json.Marshal(requestData.Addons)
+			if err == nil {
+				requestData.Product.Addons = string(addonsJSON)
+			}
+		} else {
+			requestData.Product.Addons = "[]"
+		}
+
+		// Update product fields directly on the loaded product
+		product.Name = requestData.Name
+		product.ShortDescription = requestData.ShortDescription
+		product.Description = requestData.Description
+		product.Price = requestData.Price
+		product.Category = requestData.Category
+		product.Tag = requestData.Tag
+		product.TagColor = requestData.TagColor
+		product.ImageURL1 = requestData.ImageURL1
+		product.ImageURL2 = requestData.ImageURL2
+		product.ImageURL3 = requestData.ImageURL3
+		oldStock := product.Stock
+		product.Stock = requestData.Stock
+		if requestData.LowStockThreshold != nil && *requestData.LowStockThreshold >= 0 {
+			product.LowStockThreshold = *requestData.LowStockThreshold
+		}
+		product.IsAvailable = requestData.IsAvailable
+		product.MinOrder = requestData.MinOrder
+		product.MinOrderTB = requestData.MinOrderTB
+		product.MinOrderLuarTB = requestData.MinOrderLuarTB
+		product.AvailableDaysTB = requestData.AvailableDaysTB
+		product.AvailableDaysLuarTB = requestData.AvailableDaysLuarTB
+		product.Conditions = requestData.Product.Conditions
+		product.Addons = requestData.Product.Addons
+		product.QRISId = requestData.QRISId
+
+		log.Printf("📦 Saving product with MinOrderTB=%d, MinOrderLuarTB=%d", product.MinOrderTB, product.MinOrderLuarTB)
+		log.Printf("📦 AvailableDaysTB=%v, AvailableDaysLuarTB=%v", product.AvailableDaysTB, product.AvailableDaysLuarTB)
+
+		if err := h.repo.Update(product); err != nil {
+			log.Printf("Error updating product: %v", err)
+			return fail(c, err, 500, "Failed to update product")
+		}
+
+		if skuSent {
+			if err := h.repo.SetSKU(id, requestData.SKU); err != nil {
+				log.Printf("Error updating SKU of product %s: %v", id, err)
+			}
+		}
+
+		// Stock and variants go through the inventory ledger
+		if h.hooks.Updated != nil {
+			h.hooks.Updated(c, *product, oldStock, requestData.Variants)
+		}
+
+		// Reload product with variants - use a fresh query
+		updatedProduct, err := h.repo.Find(id)
+		if err != nil {
+			log.Printf("❌ Error reloading product: %v", err)
+			return fail(c, err, 500, "Product updated but failed to reload")
+		}
+
+		log.Printf("✅ Product updated: %s with %d variants", updatedProduct.Name, len(updatedProduct.Variants))
+		log.Printf("📦 Reloaded values: MinOrderTB=%d, MinOrderLuarTB=%d", updatedProduct.MinOrderTB, updatedProduct.MinOrderLuarTB)
+		log.Printf("📦 Reloaded days: TB=%v, LuarTB=%v", updatedProduct.AvailableDaysTB, updatedProduct.AvailableDaysLuarTB)
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    updatedProduct,
+			"message": "Product updated successfully",
+		})
+	})
+
+	// Admin: Delete product
+	app.Delete("/api/admin/products/:id", func(c *fiber.Ctx) error {
+		if err := h.repo.Delete(c.Params("id")); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return fail(c, err, 404, "Product not found")
+			}
+			log.Printf("Error deleting product: %v", err)
+			return fail(c, err, 500, "Failed to delete product")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Product deleted successfully",
+		})
+	})
+
+	// Admin: Toggle product availability
+	app.Patch("/api/admin/products/:id/toggle", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		product, err := h.repo.Find(id)
+		if err != nil {
+			log.Printf("Error finding product %s: %v", id, err)
+			return fail(c, err, 404, "Product not found")
+		}
+
+		log.Printf("Toggling product %s (%s): is_available %v -> %v", id, product.Name, product.IsAvailable, !product.IsAvailable)
+
+		// Toggle availability
+		oldAvailability := product.IsAvailable
+		newAvailability := !product.IsAvailable
+
+		if err := h.repo.SetAvailability(id, newAvailability); err != nil {
+			log.Printf("Error updating product %s: %v", id, err)
+			return fail(c, err, 500, "Failed to update product")
+		}
+
+		// Update local variable
+		product.IsAvailable = newAvailability
+		product.AutoDisabled = false
+
+		log.Printf("✅ Successfully toggled product %s: is_available changed from %v to %v",
+			product.Name, oldAvailability, product.IsAvailable)
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    product,
+			"message": "Product availability updated",
+		})
+	})
+}
@@ -0,0 +1,196 @@
+package products
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newApp(repo repository.ProductRepo, hooks Hooks) *fiber.App {
+	app := fiber.New()
+	NewHandler(repo, hooks).RegisterRoutes(app)
+	return app
+}
+
+func do(t *testing.T, app *fiber.App, method, target, body string) (int, map[string]interface{}) {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, target, err)
+	}
+	var payload map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("%s %s: decode body: %v", method, target, err)
+	}
+	return resp.StatusCode, payload
+}
+
+func sku(s string) *string {
+	return &s
+}
+
+func TestCatalogueListsAvailableProducts(t *testing.T) {
+	repo := repository.NewMemoryProductRepo(
+		models.Product{Name: "Nasi Bakar", Price: 25000, IsAvailable: true},
+		models.Product{Name: "Sold Out", Price: 20000},
+	)
+	app := newApp(repo, Hooks{})
+
+	status, payload := do(t, app, "GET", "/api/products", "")
+	if status != 200 {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	products := payload["data"].([]interface{})
+	if len(products) != 1 || products[0].(map[string]interface{})["name"] != "Nasi Bakar" {
+		t.Fatalf("catalogue = %v, want only Nasi Bakar", products)
+	}
+
+	_, payload = do(t, app, "GET", "/api/products?include_sold_out=true", "")
+	if got := len(payload["data"].([]interface{})); got != 2 {
+		t.Errorf("menu with sold out products = %d, want 2", got)
+	}
+
+	_, payload = do(t, app, "GET", "/api/admin/products", "")
+	if got := len(payload["data"].([]interface{})); got != 2 {
+		t.Errorf("admin products = %d, want 2", got)
+	}
+}
+
+func TestGetUnavailableProductIsNotFound(t *testing.T) {
+	product := models.Product{Name: "Sold Out", Price: 20000}
+	repo := repository.NewMemoryProductRepo()
+	repo.Create(&product)
+	app := newApp(repo, Hooks{})
+
+	if status, _ := do(t, app, "GET", "/api/products/"+product.ID, ""); status != 404 {
+		t.Errorf("status = %d, want 404", status)
+	}
+}
+
+func TestCreateProductAppliesDefaultsAndRunsHook(t *testing.T) {
+	repo := repository.NewMemoryProductRepo()
+	var created models.Product
+	var createdVariants []models.ProductVariant
+	app := newApp(repo, Hooks{
+		Created: func(c *fiber.Ctx, product models.Product, variants []models.ProductVariant) {
+			created, createdVariants = product, variants
+		},
+	})
+
+	status, payload := do(t, app, "POST", "/api/admin/products",
+		`{"name":"Nasi Bakar","price":25000,"sku":" NB-01 ","variants":[{"name":"Jumbo","price":30000}],"conditions":[{"name":"Pedas"}]}`)
+	if status != 200 {
+		t.Fatalf("status = %d, body = %v", status, payload)
+	}
+
+	if created.ID == "" || created.Stock != 100 || created.MinOrder != 1 || !created.IsAvailable {
+		t.Errorf("hook got %+v, want stock 100, min order 1, available", created)
+	}
+	if created.SKU == nil || *created.SKU != "NB-01" {
+		t.Errorf("SKU = %v, want trimmed NB-01", created.SKU)
+	}
+	if created.Conditions != `[{"name":"Pedas"}]` || created.Addons != "[]" {
+		t.Errorf("conditions = %q, addons = %q", created.Conditions, created.Addons)
+	}
+	if len(createdVariants) != 1 || createdVariants[0].ProductID != created.ID || createdVariants[0].Stock != 100 {
+		t.Errorf("variants = %+v", createdVariants)
+	}
+
+	data := payload["data"].(map[string]interface{})
+	if got := len(data["variants"].([]interface{})); got != 1 {
+		t.Errorf("response variants = %d, want 1", got)
+	}
+}
+
+func TestCreateProductRejectsTakenSKU(t *testing.T) {
+	repo := repository.NewMemoryProductRepo(models.Product{Name: "Nasi Bakar", SKU: sku("NB-01"), IsAvailable: true})
+	app := newApp(repo, Hooks{})
+
+	status, _ := do(t, app, "POST", "/api/admin/products", `{"name":"Copy","price":1000,"sku":"NB-01"}`)
+	if status != 409 {
+		t.Errorf("status = %d, want 409", status)
+	}
+}
+
+func TestUpdateProduct(t *testing.T) {
+	product := models.Product{Name: "Nasi Bakar", Price: 25000, Stock: 10, LowStockThreshold: 5, IsAvailable: true}
+	repo := repository.NewMemoryProductRepo()
+	repo.Create(&product)
+
+	var gotOldStock, gotStock int
+	app := newApp(repo, Hooks{
+		Updated: func(c *fiber.Ctx, product models.Product, oldStock int, variants []models.ProductVariant) {
+			gotOldStock, gotStock = oldStock, product.Stock
+		},
+	})
+
+	status, payload := do(t, app, "PUT", "/api/admin/products/"+product.ID,
+		`{"name":"Nasi Bakar Spesial","price":27000,"stock":25,"is_available":true,"min_order":2,"sku":"NB-02"}`)
+	if status != 200 {
+		t.Fatalf("status = %d, body = %v", status, payload)
+	}
+
+	updated, err := repo.Find(product.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "Nasi Bakar Spesial" || updated.Price != 27000 || updated.MinOrder != 2 {
+		t.Errorf("stored product = %+v", updated)
+	}
+	if updated.LowStockThreshold != 5 {
+		t.Errorf("low stock threshold = %d, want the old 5 kept", updated.LowStockThreshold)
+	}
+	if updated.SKU == nil || *updated.SKU != "NB-02" {
+		t.Errorf("SKU = %v, want NB-02", updated.SKU)
+	}
+	// Stock is left to the inventory hook
+	if gotOldStock != 10 || gotStock != 25 {
+		t.Errorf("hook stock = %d -> %d, want 10 -> 25", gotOldStock, gotStock)
+	}
+}
+
+func TestToggleProduct(t *testing.T) {
+	product := models.Product{Name: "Nasi Bakar", IsAvailable: true}
+	repo := repository.NewMemoryProductRepo()
+	repo.Create(&product)
+	app := newApp(repo, Hooks{})
+
+	status, payload := do(t, app, "PATCH", "/api/admin/products/"+product.ID+"/toggle", "")
+	if status != 200 {
+		t.Fatalf("status = %d, body = %v", status, payload)
+	}
+	if payload["data"].(map[string]interface{})["is_available"] != false {
+		t.Errorf("is_available = %v, want false", payload["data"])
+	}
+	if stored, _ := repo.Find(product.ID); stored.IsAvailable {
+		t.Error("stored product still available")
+	}
+}
+
+func TestDeleteProduct(t *testing.T) {
+	product := models.Product{Name: "Nasi Bakar"}
+	repo := repository.NewMemoryProductRepo()
+	repo.Create(&product)
+	app := newApp(repo, Hooks{})
+
+	if status, _ := do(t, app, "DELETE", "/api/admin/products/"+product.ID, ""); status != 200 {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	if status, _ := do(t, app, "DELETE", "/api/admin/products/"+product.ID, ""); status != 404 {
+		t.Errorf("second delete: status = %d, want 404", status)
+	}
+}
@@ -0,0 +1,173 @@
+// Package qris manages the QRIS payment codes products are paid with.
+package qris
+
+import (
+	"fmt"
+	"log"
+
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RegisterRoutes mounts QRIS management and the QRIS lookup of a product
+func RegisterRoutes(app fiber.Router) {
+	// Get all QRIS codes
+	app.Get("/api/admin/qris", func(c *fiber.Ctx) error {
+		var qrisCodes []models.QRISCode
+		result := db.DB.Order("created_at DESC").Find(&qrisCodes)
+		if result.Error != nil {
+			log.Printf("Error fetching QRIS codes: %v", result.Error)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to fetch QRIS codes",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    qrisCodes,
+		})
+	})
+
+	// Create QRIS code
+	app.Post("/api/admin/qris", func(c *fiber.Ctx) error {
+		var qris models.QRISCode
+		if err := c.BodyParser(&qris); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+
+		if qris.Name == "" || qris.ImageURL == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Name and image URL are required",
+			})
+		}
+
+		qris.IsActive = true
+		result := db.DB.Create(&qris)
+		if result.Error != nil {
+			log.Printf("Error creating QRIS: %v", result.Error)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to create QRIS",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    qris,
+			"message": "QRIS created successfully",
+		})
+	})
+
+	// Update QRIS code
+	app.Put("/api/admin/qris/:id", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		var qris models.QRISCode
+
+		if err := db.DB.First(&qris, "id = ?", id).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "QRIS not found",
+			})
+		}
+
+		var updateData models.QRISCode
+		if err := c.BodyParser(&updateData); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+
+		result := db.DB.Model(&qris).Updates(updateData)
+		if result.Error != nil {
+			log.Printf("Error updating QRIS: %v", result.Error)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to update QRIS",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    qris,
+			"message": "QRIS updated successfully",
+		})
+	})
+
+	// Delete QRIS code
+	app.Delete("/api/admin/qris/:id", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		// Check if any products are using this QRIS
+		var count int64
+		db.DB.Model(&models.Product{}).Where("qris_id = ?", id).Count(&count)
+		if count > 0 {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("Tidak dapat menghapus QRIS. Masih digunakan oleh %d produk", count),
+			})
+		}
+
+		result := db.DB.Delete(&models.QRISCode{}, "id = ?", id)
+		if result.Error != nil {
+			log.Printf("Error deleting QRIS: %v", result.Error)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to delete QRIS",
+			})
+		}
+
+		if result.RowsAffected == 0 {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "QRIS not found",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "QRIS deleted successfully",
+		})
+	})
+
+	// Get QRIS by product ID
+	app.Get("/api/products/:id/qris", func(c *fiber.Ctx) error {
+		var product models.Product
+		if err := db.DB.First(&product, "id = ?", c.Params("id")).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Product not found",
+			})
+		}
+
+		// If product has no QRIS assigned, return null
+		if product.QRISId == nil {
+			return c.JSON(fiber.Map{
+				"success": true,
+				"data":    nil,
+				"message": "No QRIS assigned to this product",
+			})
+		}
+
+		// Get QRIS details
+		var qris models.QRISCode
+		if err := db.DB.First(&qris, "id = ?", *product.QRISId).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "QRIS not found",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    qris,
+		})
+	})
+}
@@ -0,0 +1,79 @@
+package realtime
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Event types
+const (
+	EventOrderCreated       = "order.created"
+	EventOrderStatusChanged = "order.status_changed"
+	EventPaymentUpdated     = "payment.updated"
+	// Sent first on customer streams, never stored
+	EventOrderSnapshot = "order.snapshot"
+)
+
+// NotifyChannel is the Postgres channel a trigger on order_events notifies
+const NotifyChannel = "order_events"
+
+// Advisory lock held by Publish until its transaction commits
+const publishLockKey = 4_620_195_020
+
+// Event is one row of the order_events log, its ID is the SSE event id
+type Event struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	Type      string    `gorm:"not null" json:"type"`
+	OrderID   string    `gorm:"type:uuid" json:"order_id"`
+	Data      string    `gorm:"type:jsonb;not null" json:"data"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Event) TableName() string {
+	return "order_events"
+}
+
+// Publish stores an event, the insert trigger notifies every listener.
+//
+// Readers resume with id > lastID, so ids must be handed out in commit order:
+// a BIGSERIAL id is taken at insert time, and a lower id committing after a
+// higher one was read would be skipped for good. The lock makes the next
+// publish wait for the previous one to commit before it takes an id.
+func Publish(db *gorm.DB, eventType, orderID string, data interface{}) (*Event, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	event := Event{
+		Type:    eventType,
+		OrderID: orderID,
+		Data:    string(encoded),
+	}
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", publishLockKey).Error; err != nil {
+			return err
+		}
+		return tx.Create(&event).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// After returns events with an id greater than afterID, oldest first. Ids
+// follow commit order (see Publish), so nothing below afterID shows up later.
+func After(db *gorm.DB, afterID int64, limit int) ([]Event, error) {
+	var events []Event
+	err := db.Where("id > ?", afterID).Order("id ASC").Limit(limit).Find(&events).Error
+	return events, err
+}
+
+// LatestID returns the id of the newest event, 0 if there are none
+func LatestID(db *gorm.DB) (int64, error) {
+	var id int64
+	err := db.Model(&Event{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+	return id, err
+}
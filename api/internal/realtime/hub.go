@@ -0,0 +1,69 @@
+package realtime
+
+import (
+	"sync"
+)
+
+// Subscriber receives events accepted by its filter
+type Subscriber struct {
+	Events chan Event
+	// Closed when the subscriber fell behind and missed an event
+	Overflow chan struct{}
+	filter   func(Event) bool
+	once     sync.Once
+}
+
+// Hub fans events out to the SSE connections of this process
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[*Subscriber]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{subscribers: make(map[*Subscriber]struct{})}
+}
+
+// Subscribe to events, a nil filter accepts everything
+func (h *Hub) Subscribe(filter func(Event) bool) *Subscriber {
+	sub := &Subscriber{
+		Events:   make(chan Event, 64),
+		Overflow: make(chan struct{}),
+		filter:   filter,
+	}
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+// Unsubscribe stops delivery to sub
+func (h *Hub) Unsubscribe(sub *Subscriber) {
+	h.mu.Lock()
+	delete(h.subscribers, sub)
+	h.mu.Unlock()
+}
+
+// Broadcast an event, a slow subscriber is flagged through Overflow rather
+// than blocking the hub, so it can reconnect and resume with Last-Event-ID
+func (h *Hub) Broadcast(event Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subscribers {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.Events <- event:
+		default:
+			sub.once.Do(func() { close(sub.Overflow) })
+		}
+	}
+}
+
+// Count returns the number of connected subscribers
+func (h *Hub) Count() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers)
+}
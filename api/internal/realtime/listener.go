@@ -0,0 +1,87 @@
+package realtime
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// Listen forwards order_events rows to the hub as Postgres notifies about
+// them, so every replica sees every event. Rows are read from the table in
+// id order, which also fills gaps left by dropped connections.
+func Listen(ctx context.Context, dsn string, db func() *gorm.DB, hub *Hub) {
+	listener := pq.NewListener(dsn, time.Second, 30*time.Second, func(ev pq.ListenerEventType, err error) {
+		switch ev {
+		case pq.ListenerEventConnected:
+			log.Println("📡 Realtime listener connected")
+		case pq.ListenerEventDisconnected:
+			log.Printf("⚠️ Realtime listener disconnected: %v", err)
+		case pq.ListenerEventReconnected:
+			log.Println("📡 Realtime listener reconnected")
+		case pq.ListenerEventConnectionAttemptFailed:
+			log.Printf("⚠️ Realtime listener connection failed: %v", err)
+		}
+	})
+
+	go func() {
+		// Blocks until the first connection succeeds
+		if err := listener.Listen(NotifyChannel); err != nil {
+			log.Printf("❌ Realtime LISTEN %s failed: %v", NotifyChannel, err)
+		}
+	}()
+
+	go func() {
+		defer listener.Close()
+
+		lastID := int64(-1)
+		ping := time.NewTicker(time.Minute)
+		defer ping.Stop()
+
+		for {
+			// Start from the newest event, older ones are only replayed on request
+			if lastID < 0 {
+				if conn := db(); conn != nil {
+					if latest, err := LatestID(conn); err == nil {
+						lastID = latest
+					}
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-listener.Notify:
+				// nil after a reconnect, either way read what is new
+			case <-ping.C:
+				go listener.Ping()
+			}
+
+			conn := db()
+			if conn == nil || lastID < 0 {
+				continue
+			}
+			lastID = catchUp(conn, hub, lastID)
+		}
+	}()
+}
+
+// Broadcast every event after lastID, returns the new lastID
+func catchUp(db *gorm.DB, hub *Hub, lastID int64) int64 {
+	for {
+		events, err := After(db, lastID, 500)
+		if err != nil {
+			log.Printf("❌ Realtime catch-up failed: %v", err)
+			return lastID
+		}
+		for _, event := range events {
+			hub.Broadcast(event)
+			lastID = event.ID
+		}
+		if len(events) < 500 {
+			return lastID
+		}
+	}
+}
@@ -0,0 +1,288 @@
+// Package report computes the admin dashboard numbers and the financial report.
+package report
+
+import (
+	"log"
+	"time"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Dashboard Stats Response
+type DashboardStats struct {
+	PeriodDays            int            `json:"period_days"`
+	TotalCustomers        int64          `json:"total_customers"`
+	NewCustomers          int64          `json:"new_customers"`
+	ReturningCustomers    int64          `json:"returning_customers"`
+	RepeatPurchaseRate    float64        `json:"repeat_purchase_rate"`
+	CustomerLifetimeValue float64        `json:"customer_lifetime_value"`
+	TotalOrders           int64          `json:"total_orders"`
+	TotalRevenue          float64        `json:"total_revenue"`
+	ActiveOrders          int64          `json:"active_orders"`
+	CustomerGrowth        float64        `json:"customer_growth"`
+	OrderGrowth           float64        `json:"order_growth"`
+	RevenueGrowth         float64        `json:"revenue_growth"`
+	ActiveOrdersList      []models.Order `json:"active_orders_list"`
+}
+
+// Row returned by dashboardStatsSQL
+type dashboardAggregate struct {
+	TotalOrders          int64
+	TotalRevenue         float64
+	ActiveOrders         int64
+	OrdersCurrent        int64
+	OrdersPrevious       int64
+	RevenueCurrent       float64
+	RevenuePrevious      float64
+	TotalCustomers       int64
+	NewCustomersCurrent  int64
+	NewCustomersPrevious int64
+	ReturningCustomers   int64
+	RepeatCustomers      int64
+	CustomerRevenue      float64
+}
+
+// Identifies a customer across orders: digits-only phone with a leading 0
+// rewritten to 62, falling back to the lower-cased email
+const customerKeySQL = `COALESCE(
+	NULLIF(regexp_replace(regexp_replace(customer_phone, '[^0-9]', '', 'g'), '^0', '62'), ''),
+	LOWER(TRIM(customer_email))
+)`
+
+// All dashboard numbers in one pass over orders
+const dashboardStatsSQL = `
+WITH customers AS (
+	SELECT
+		` + customerKeySQL + ` AS customer_key,
+		MIN(created_at) AS first_order_at,
+		COUNT(*) AS order_count,
+		COALESCE(SUM(total) FILTER (WHERE payment_status = 'paid'), 0) AS revenue,
+		BOOL_OR(created_at >= @current) AS ordered_current
+	FROM orders
+	WHERE order_status NOT IN @cancelled
+	GROUP BY 1
+),
+order_totals AS (
+	SELECT
+		COUNT(*) AS total_orders,
+		COALESCE(SUM(total) FILTER (WHERE payment_status = 'paid'), 0) AS total_revenue,
+		COUNT(*) FILTER (WHERE order_status IN @active) AS active_orders,
+		COUNT(*) FILTER (WHERE created_at >= @current) AS orders_current,
+		COUNT(*) FILTER (WHERE created_at >= @previous AND created_at < @current) AS orders_previous,
+		COALESCE(SUM(total) FILTER (WHERE payment_status = 'paid' AND created_at >= @current), 0) AS revenue_current,
+		COALESCE(SUM(total) FILTER (WHERE payment_status = 'paid' AND created_at >= @previous AND created_at < @current), 0) AS revenue_previous
+	FROM orders
+),
+customer_totals AS (
+	SELECT
+		COUNT(*) AS total_customers,
+		COUNT(*) FILTER (WHERE first_order_at >= @current) AS new_customers_current,
+		COUNT(*) FILTER (WHERE first_order_at >= @previous AND first_order_at < @current) AS new_customers_previous,
+		COUNT(*) FILTER (WHERE ordered_current AND first_order_at < @current) AS returning_customers,
+		COUNT(*) FILTER (WHERE order_count > 1) AS repeat_customers,
+		COALESCE(SUM(revenue), 0) AS customer_revenue
+	FROM customers
+)
+SELECT * FROM order_totals CROSS JOIN customer_totals`
+
+// Percentage change from previous to current, 100 when starting from zero
+func growthPercent(current, previous float64) float64 {
+	if previous > 0 {
+		return (current - previous) / previous * 100
+	}
+	if current > 0 {
+		return 100
+	}
+	return 0
+}
+
+// RegisterRoutes mounts the dashboard statistics and the financial report,
+// both for admins only
+func RegisterRoutes(app fiber.Router) {
+	// Dashboard statistics endpoint
+	// Optional ?days=N sets the comparison period (default 7, max 365)
+	app.Get("/api/dashboard/stats", auth.RequireSession, auth.RequireAdmin, func(c *fiber.Ctx) error {
+		days := c.QueryInt("days", 7)
+		if days < 1 || days > 365 {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "days must be between 1 and 365",
+			})
+		}
+
+		// Current period vs the period of equal length before it
+		now := time.Now()
+		currentStart := now.AddDate(0, 0, -days)
+		previousStart := now.AddDate(0, 0, -2*days)
+
+		var agg dashboardAggregate
+		err := db.DB.Raw(dashboardStatsSQL, map[string]interface{}{
+			"current":   currentStart,
+			"previous":  previousStart,
+			"active":    models.ActiveOrderStatuses,
+			"cancelled": models.CancelledOrderStatuses,
+		}).Scan(&agg).Error
+		if err != nil {
+			log.Printf("Error computing dashboard stats: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to compute dashboard stats",
+			})
+		}
+
+		stats := DashboardStats{
+			PeriodDays:         days,
+			TotalCustomers:     agg.TotalCustomers,
+			NewCustomers:       agg.NewCustomersCurrent,
+			ReturningCustomers: agg.ReturningCustomers,
+			TotalOrders:        agg.TotalOrders,
+			TotalRevenue:       agg.TotalRevenue,
+			ActiveOrders:       agg.ActiveOrders,
+			CustomerGrowth:     growthPercent(float64(agg.NewCustomersCurrent), float64(agg.NewCustomersPrevious)),
+			OrderGrowth:        growthPercent(float64(agg.OrdersCurrent), float64(agg.OrdersPrevious)),
+			RevenueGrowth:      growthPercent(agg.RevenueCurrent, agg.RevenuePrevious),
+		}
+		if agg.TotalCustomers > 0 {
+			stats.RepeatPurchaseRate = float64(agg.RepeatCustomers) / float64(agg.TotalCustomers) * 100
+			stats.CustomerLifetimeValue = agg.CustomerRevenue / float64(agg.TotalCustomers)
+		}
+
+		// Get active orders list
+		db.DB.Where("order_status IN ?", models.ActiveOrderStatuses).
+			Order("created_at DESC").
+			Limit(10).
+			Find(&stats.ActiveOrdersList)
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    stats,
+		})
+	})
+
+	// Get financial report (admin)
+	app.Get("/api/reports", auth.RequireSession, auth.RequireAdmin, func(c *fiber.Ctx) error {
+		startDate := c.Query("start_date")
+		endDate := c.Query("end_date")
+
+		if startDate == "" {
+			startDate = time.Now().AddDate(0, 0, -30).Format("2006-01-02")
+		}
+		if endDate == "" {
+			endDate = time.Now().Format("2006-01-02")
+		}
+
+		// Margins only cover completed orders, their COGS is frozen at completion
+		type ProductSales struct {
+			ProductID        string  `json:"product_id"`
+			ProductName      string  `json:"product_name"`
+			TotalQuantity    int     `json:"total_quantity"`
+			TotalRevenue     float64 `json:"total_revenue"`
+			OrderCount       int     `json:"order_count"`
+			CompletedRevenue float64 `json:"completed_revenue"`
+			TotalCOGS        float64 `gorm:"column:total_cogs" json:"total_cogs"`
+			GrossMargin      float64 `json:"gross_margin"`
+			MarginPercent    float64 `json:"margin_percent"`
+			HasRecipe        bool    `json:"has_recipe"`
+		}
+
+		type DailySales struct {
+			Date             string  `json:"date"`
+			Revenue          float64 `json:"revenue"`
+			Orders           int     `json:"orders"`
+			CompletedRevenue float64 `json:"completed_revenue"`
+			COGS             float64 `gorm:"column:cogs" json:"cogs"`
+			GrossMargin      float64 `json:"gross_margin"`
+			MarginPercent    float64 `json:"margin_percent"`
+		}
+
+		type ReportData struct {
+			TotalRevenue      float64        `json:"total_revenue"`
+			TotalOrders       int            `json:"total_orders"`
+			TotalProductsSold int            `json:"total_products_sold"`
+			AverageOrderValue float64        `json:"average_order_value"`
+			CompletedRevenue  float64        `json:"completed_revenue"`
+			TotalCOGS         float64        `gorm:"column:total_cogs" json:"total_cogs"`
+			GrossMargin       float64        `json:"gross_margin"`
+			MarginPercent     float64        `json:"margin_percent"`
+			ProductSales      []ProductSales `json:"product_sales"`
+			DailySales        []DailySales   `json:"daily_sales"`
+		}
+
+		marginPercent := func(margin, revenue float64) float64 {
+			if revenue == 0 {
+				return 0
+			}
+			return margin / revenue * 100
+		}
+
+		report := ReportData{}
+
+		// Excluded statuses: cancelled orders should not be counted
+		excludedStatuses := []string{"cancelled", "deleted"}
+
+		// Get total revenue and orders (all orders except cancelled/deleted)
+		db.DB.Model(&models.Order{}).
+			Where("order_status NOT IN ? AND DATE(created_at) BETWEEN ? AND ?", excludedStatuses, startDate, endDate).
+			Select("COALESCE(SUM(total), 0) as total_revenue, COUNT(*) as total_orders, " +
+				"COALESCE(SUM(total) FILTER (WHERE order_status = 'completed'), 0) as completed_revenue, " +
+				"COALESCE(SUM(cogs) FILTER (WHERE order_status = 'completed'), 0) as total_cogs").
+			Scan(&report)
+		report.GrossMargin = report.CompletedRevenue - report.TotalCOGS
+		report.MarginPercent = marginPercent(report.GrossMargin, report.CompletedRevenue)
+
+		// Get total products sold (all orders except cancelled/deleted)
+		db.DB.Table("order_items").
+			Joins("JOIN orders ON order_items.order_id = orders.id").
+			Where("orders.order_status NOT IN ? AND DATE(orders.created_at) BETWEEN ? AND ?", excludedStatuses, startDate, endDate).
+			Select("COALESCE(SUM(order_items.quantity), 0)").
+			Scan(&report.TotalProductsSold)
+
+		// Calculate average order value
+		if report.TotalOrders > 0 {
+			report.AverageOrderValue = report.TotalRevenue / float64(report.TotalOrders)
+		}
+
+		// Get product sales (all orders except cancelled/deleted)
+		var productSales []ProductSales
+		db.DB.Table("products").
+			Select("products.id as product_id, products.name as product_name, COALESCE(SUM(order_items.quantity), 0) as total_quantity, COALESCE(SUM(order_items.subtotal), 0) as total_revenue, COUNT(DISTINCT orders.id) as order_count, "+
+				"COALESCE(SUM(order_items.subtotal) FILTER (WHERE orders.order_status = 'completed'), 0) as completed_revenue, "+
+				"COALESCE(SUM(order_items.cogs) FILTER (WHERE orders.order_status = 'completed'), 0) as total_cogs, "+
+				"EXISTS (SELECT 1 FROM recipes WHERE recipes.product_id = products.id) as has_recipe").
+			Joins("LEFT JOIN order_items ON products.id = order_items.product_id").
+			Joins("LEFT JOIN orders ON order_items.order_id = orders.id AND orders.order_status NOT IN ? AND DATE(orders.created_at) BETWEEN ? AND ?", excludedStatuses, startDate, endDate).
+			Group("products.id, products.name").
+			Having("COALESCE(SUM(order_items.quantity), 0) > 0").
+			Order("total_revenue DESC").
+			Scan(&productSales)
+		for i := range productSales {
+			productSales[i].GrossMargin = productSales[i].CompletedRevenue - productSales[i].TotalCOGS
+			productSales[i].MarginPercent = marginPercent(productSales[i].GrossMargin, productSales[i].CompletedRevenue)
+		}
+		report.ProductSales = productSales
+
+		// Get daily sales (all orders except cancelled/deleted)
+		var dailySales []DailySales
+		db.DB.Table("orders").
+			Select("DATE(created_at) as date, COALESCE(SUM(total), 0) as revenue, COUNT(*) as orders, "+
+				"COALESCE(SUM(total) FILTER (WHERE order_status = 'completed'), 0) as completed_revenue, "+
+				"COALESCE(SUM(cogs) FILTER (WHERE order_status = 'completed'), 0) as cogs").
+			Where("order_status NOT IN ? AND DATE(created_at) BETWEEN ? AND ?", excludedStatuses, startDate, endDate).
+			Group("DATE(created_at)").
+			Order("date ASC").
+			Scan(&dailySales)
+		for i := range dailySales {
+			dailySales[i].GrossMargin = dailySales[i].CompletedRevenue - dailySales[i].COGS
+			dailySales[i].MarginPercent = marginPercent(dailySales[i].GrossMargin, dailySales[i].CompletedRevenue)
+		}
+		report.DailySales = dailySales
+
+		log.Printf("📊 Report generated: %s to %s", startDate, endDate)
+
+		return c.JSON(report)
+	})
+}
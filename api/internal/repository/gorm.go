@@ -0,0 +1,556 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"scaff-food-backend/internal/models"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// gormRepo looks the connection up on every call, so repositories built
+// before the database came up start working once it does
+type gormRepo struct {
+	conn func() *gorm.DB
+}
+
+func (r gormRepo) db() (*gorm.DB, error) {
+	conn := r.conn()
+	if conn == nil {
+		return nil, ErrUnavailable
+	}
+	return conn, nil
+}
+
+func notFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
+
+var (
+	_ ProductRepo  = (*GormProductRepo)(nil)
+	_ OrderRepo    = (*GormOrderRepo)(nil)
+	_ EventRepo    = (*GormEventRepo)(nil)
+	_ SettingsRepo = (*GormSettingsRepo)(nil)
+)
+
+// ==================== PRODUCTS ====================
+
+// GormProductRepo is the Postgres ProductRepo
+type GormProductRepo struct{ gormRepo }
+
+// NewGormProductRepo returns a ProductRepo on the connection conn returns
+func NewGormProductRepo(conn func() *gorm.DB) *GormProductRepo {
+	return &GormProductRepo{gormRepo{conn}}
+}
+
+func (r *GormProductRepo) First() (*models.Product, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var product models.Product
+	if err := db.First(&product).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &product, nil
+}
+
+func (r *GormProductRepo) ListAvailable() ([]models.Product, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var products []models.Product
+	err = db.Preload("Variants").Where("is_available = ?", true).Find(&products).Error
+	return products, err
+}
+
+func (r *GormProductRepo) List() ([]models.Product, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var products []models.Product
+	err = db.Preload("Variants").Order("created_at DESC").Find(&products).Error
+	return products, err
+}
+
+func (r *GormProductRepo) FindAvailable(id string) (*models.Product, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var product models.Product
+	if err := db.Preload("Variants").Where("id = ? AND is_available = ?", id, true).First(&product).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &product, nil
+}
+
+func (r *GormProductRepo) Find(id string) (*models.Product, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var product models.Product
+	if err := db.Preload("Variants").First(&product, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &product, nil
+}
+
+func (r *GormProductRepo) SKUTaken(sku, exceptID string) (bool, error) {
+	db, err := r.db()
+	if err != nil {
+		return false, err
+	}
+	query := db.Model(&models.Product{}).Where("sku = ?", sku)
+	if exceptID != "" {
+		query = query.Where("id <> ?", exceptID)
+	}
+	var count int64
+	err = query.Count(&count).Error
+	return count > 0, err
+}
+
+func (r *GormProductRepo) Create(product *models.Product) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	return db.Omit("Variants").Create(product).Error
+}
+
+func (r *GormProductRepo) CreateVariants(variants []models.ProductVariant) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	if len(variants) == 0 {
+		return nil
+	}
+	return db.Create(&variants).Error
+}
+
+func (r *GormProductRepo) Update(product *models.Product) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+
+	// Raw SQL so zero values and the text[] columns are always written
+	return db.Exec(`
+		UPDATE products SET
+			name = ?,
+			short_description = ?,
+			description = ?,
+			price = ?,
+			category = ?,
+			tag = ?,
+			tag_color = ?,
+			image_url_1 = ?,
+			image_url_2 = ?,
+			image_url_3 = ?,
+			low_stock_threshold = ?,
+			is_available = ?,
+			min_order = ?,
+			min_order_tb = ?,
+			min_order_luar_tb = ?,
+			available_days_tb = ?,
+			available_days_luar_tb = ?,
+			conditions = ?,
+			addons = ?,
+			qris_id = ?,
+			updated_at = NOW()
+		WHERE id = ?
+	`,
+		product.Name,
+		product.ShortDescription,
+		product.Description,
+		product.Price,
+		product.Category,
+		product.Tag,
+		product.TagColor,
+		product.ImageURL1,
+		product.ImageURL2,
+		product.ImageURL3,
+		product.LowStockThreshold,
+		product.IsAvailable,
+		product.MinOrder,
+		product.MinOrderTB,
+		product.MinOrderLuarTB,
+		pq.Array(product.AvailableDaysTB),
+		pq.Array(product.AvailableDaysLuarTB),
+		product.Conditions,
+		product.Addons,
+		product.QRISId,
+		product.ID,
+	).Error
+}
+
+func (r *GormProductRepo) SetSKU(id string, sku *string) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	return db.Model(&models.Product{}).Where("id = ?", id).Update("sku", sku).Error
+}
+
+func (r *GormProductRepo) SetAvailability(id string, available bool) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	// A map so the false boolean is written too
+	return db.Model(&models.Product{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"is_available":  available,
+		"auto_disabled": false,
+	}).Error
+}
+
+func (r *GormProductRepo) Delete(id string) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	result := db.Delete(&models.Product{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ==================== ORDERS ====================
+
+// GormOrderRepo is the Postgres OrderRepo
+type GormOrderRepo struct{ gormRepo }
+
+// NewGormOrderRepo returns an OrderRepo on the connection conn returns
+func NewGormOrderRepo(conn func() *gorm.DB) *GormOrderRepo {
+	return &GormOrderRepo{gormRepo{conn}}
+}
+
+func (r *GormOrderRepo) Create(order *models.Order, items []models.OrderItem) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].OrderID = order.ID
+			if err := tx.Create(&items[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Attach the items of every order
+func withItems(db *gorm.DB, orders []models.Order) ([]models.OrderWithItems, error) {
+	ordersWithItems := make([]models.OrderWithItems, 0, len(orders))
+	for _, order := range orders {
+		var items []models.OrderItem
+		if err := db.Where("order_id = ?", order.ID).Find(&items).Error; err != nil {
+			return nil, err
+		}
+		ordersWithItems = append(ordersWithItems, models.OrderWithItems{
+			Order: order,
+			Items: items,
+		})
+	}
+	return ordersWithItems, nil
+}
+
+func (r *GormOrderRepo) List() ([]models.OrderWithItems, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var orders []models.Order
+	if err := db.Order("created_at DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return withItems(db, orders)
+}
+
+func (r *GormOrderRepo) ListByCustomer(email, phone string) ([]models.OrderWithItems, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	query := db.Where("customer_email = ?", email)
+	if phone != "" {
+		query = query.Where("customer_phone = ?", phone)
+	}
+	var orders []models.Order
+	if err := query.Order("created_at DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return withItems(db, orders)
+}
+
+func (r *GormOrderRepo) Find(id string) (*models.Order, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var order models.Order
+	if err := db.First(&order, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &order, nil
+}
+
+func (r *GormOrderRepo) Items(orderID string) ([]models.OrderItem, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var items []models.OrderItem
+	err = db.Where("order_id = ?", orderID).Find(&items).Error
+	return items, err
+}
+
+func (r *GormOrderRepo) CountByStatus(status string) (int64, error) {
+	db, err := r.db()
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	err = db.Model(&models.Order{}).Where("order_status = ?", status).Count(&count).Error
+	return count, err
+}
+
+func (r *GormOrderRepo) SaveStatus(order *models.Order, oldStatus, oldPaymentStatus string) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	result := db.Model(order).
+		Where("order_status = ? AND payment_status = ?", oldStatus, oldPaymentStatus).
+		Select("order_status", "payment_status", "cancellation_reason", "cancelled_at", "delivery_photo", "appreciation_message").
+		Updates(order)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected != 1 {
+		return ErrConflict
+	}
+	return nil
+}
+
+func (r *GormOrderRepo) DeleteCancelledBefore(t time.Time) (int64, error) {
+	db, err := r.db()
+	if err != nil {
+		return 0, err
+	}
+	result := db.Where("order_status IN ? AND cancelled_at < ?", models.CancelledOrderStatuses, t).Delete(&models.Order{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *GormOrderRepo) Delete(id string) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	// Items are deleted by CASCADE
+	result := db.Delete(&models.Order{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ==================== EVENTS ====================
+
+// GormEventRepo is the Postgres EventRepo
+type GormEventRepo struct{ gormRepo }
+
+// NewGormEventRepo returns an EventRepo on the connection conn returns
+func NewGormEventRepo(conn func() *gorm.DB) *GormEventRepo {
+	return &GormEventRepo{gormRepo{conn}}
+}
+
+// Attach the comment count of every event
+func withCommentCounts(db *gorm.DB, events []models.Event) ([]models.EventWithComments, error) {
+	eventsWithCount := make([]models.EventWithComments, 0, len(events))
+	for _, event := range events {
+		var commentCount int64
+		if err := db.Model(&models.EventComment{}).Where("event_id = ?", event.ID).Count(&commentCount).Error; err != nil {
+			return nil, err
+		}
+		eventsWithCount = append(eventsWithCount, models.EventWithComments{
+			Event:        event,
+			CommentCount: int(commentCount),
+		})
+	}
+	return eventsWithCount, nil
+}
+
+func (r *GormEventRepo) ListActive() ([]models.EventWithComments, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var events []models.Event
+	if err := db.Where("is_active = ?", true).Order("created_at DESC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return withCommentCounts(db, events)
+}
+
+func (r *GormEventRepo) List() ([]models.EventWithComments, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var events []models.Event
+	if err := db.Order("created_at DESC").Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return withCommentCounts(db, events)
+}
+
+func (r *GormEventRepo) FindActive(id string) (*models.Event, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var event models.Event
+	if err := db.Where("id = ? AND is_active = ?", id, true).First(&event).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &event, nil
+}
+
+func (r *GormEventRepo) Find(id string) (*models.Event, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var event models.Event
+	if err := db.First(&event, "id = ?", id).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &event, nil
+}
+
+func (r *GormEventRepo) Create(event *models.Event) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	return db.Create(event).Error
+}
+
+func (r *GormEventRepo) Update(event *models.Event, changes models.Event) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	return db.Model(event).Updates(changes).Error
+}
+
+func (r *GormEventRepo) Delete(id string) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	return db.Delete(&models.Event{}, "id = ?", id).Error
+}
+
+func (r *GormEventRepo) Comments(eventID string) ([]models.EventComment, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var comments []models.EventComment
+	err = db.Where("event_id = ? AND parent_id IS NULL", eventID).Order("created_at DESC").Find(&comments).Error
+	return comments, err
+}
+
+func (r *GormEventRepo) CountComments(eventID string) (int64, error) {
+	db, err := r.db()
+	if err != nil {
+		return 0, err
+	}
+	var count int64
+	err = db.Model(&models.EventComment{}).Where("event_id = ?", eventID).Count(&count).Error
+	return count, err
+}
+
+func (r *GormEventRepo) Replies(commentID string) ([]models.EventComment, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var replies []models.EventComment
+	err = db.Where("parent_id = ?", commentID).Order("created_at ASC").Find(&replies).Error
+	return replies, err
+}
+
+func (r *GormEventRepo) AddComment(comment *models.EventComment) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	return db.Create(comment).Error
+}
+
+func (r *GormEventRepo) DeleteComment(id string) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	return db.Delete(&models.EventComment{}, "id = ?", id).Error
+}
+
+// ==================== SETTINGS ====================
+
+// GormSettingsRepo is the Postgres SettingsRepo
+type GormSettingsRepo struct{ gormRepo }
+
+// NewGormSettingsRepo returns a SettingsRepo on the connection conn returns
+func NewGormSettingsRepo(conn func() *gorm.DB) *GormSettingsRepo {
+	return &GormSettingsRepo{gormRepo{conn}}
+}
+
+func (r *GormSettingsRepo) Get(key string) (*models.Setting, error) {
+	db, err := r.db()
+	if err != nil {
+		return nil, err
+	}
+	var setting models.Setting
+	if err := db.Where("key = ?", key).First(&setting).Error; err != nil {
+		return nil, notFound(err)
+	}
+	return &setting, nil
+}
+
+func (r *GormSettingsRepo) Set(key, value string) error {
+	db, err := r.db()
+	if err != nil {
+		return err
+	}
+	return db.Exec(`
+		INSERT INTO settings (key, value, updated_at)
+		VALUES (?, ?, CURRENT_TIMESTAMP)
+		ON CONFLICT (key)
+		DO UPDATE SET value = ?, updated_at = CURRENT_TIMESTAMP
+	`, key, value, value).Error
+}
@@ -0,0 +1,623 @@
+package repository
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"scaff-food-backend/internal/models"
+)
+
+// The in-memory repositories keep rows in insertion order, newest first
+// listings walk them backwards. They are meant for tests, not production.
+//
+// Fiber reuses the memory behind route params once a request is done, so
+// strings that may come from c.Params are cloned before they are kept.
+
+// Random UUID v4, in the format Postgres generates
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+var (
+	_ ProductRepo  = (*MemoryProductRepo)(nil)
+	_ OrderRepo    = (*MemoryOrderRepo)(nil)
+	_ EventRepo    = (*MemoryEventRepo)(nil)
+	_ SettingsRepo = (*MemorySettingsRepo)(nil)
+)
+
+// ==================== PRODUCTS ====================
+
+// MemoryProductRepo is an in-memory ProductRepo
+type MemoryProductRepo struct {
+	mu       sync.Mutex
+	products []models.Product
+	variants []models.ProductVariant
+}
+
+// NewMemoryProductRepo returns a ProductRepo holding products
+func NewMemoryProductRepo(products ...models.Product) *MemoryProductRepo {
+	r := &MemoryProductRepo{}
+	for i := range products {
+		r.Create(&products[i])
+		r.CreateVariants(products[i].Variants)
+	}
+	return r
+}
+
+func (r *MemoryProductRepo) index(id string) int {
+	for i := range r.products {
+		if r.products[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Copy of a product with its variants attached
+func (r *MemoryProductRepo) load(product models.Product) models.Product {
+	product.Variants = nil
+	for _, variant := range r.variants {
+		if variant.ProductID == product.ID {
+			product.Variants = append(product.Variants, variant)
+		}
+	}
+	return product
+}
+
+func (r *MemoryProductRepo) First() (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.products) == 0 {
+		return nil, ErrNotFound
+	}
+	product := r.load(r.products[0])
+	return &product, nil
+}
+
+func (r *MemoryProductRepo) ListAvailable() ([]models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var products []models.Product
+	for _, product := range r.products {
+		if product.IsAvailable {
+			products = append(products, r.load(product))
+		}
+	}
+	return products, nil
+}
+
+func (r *MemoryProductRepo) List() ([]models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var products []models.Product
+	for i := len(r.products) - 1; i >= 0; i-- {
+		products = append(products, r.load(r.products[i]))
+	}
+	return products, nil
+}
+
+func (r *MemoryProductRepo) FindAvailable(id string) (*models.Product, error) {
+	product, err := r.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if !product.IsAvailable {
+		return nil, ErrNotFound
+	}
+	return product, nil
+}
+
+func (r *MemoryProductRepo) Find(id string) (*models.Product, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	product := r.load(r.products[i])
+	return &product, nil
+}
+
+func (r *MemoryProductRepo) SKUTaken(sku, exceptID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, product := range r.products {
+		if product.SKU != nil && *product.SKU == sku && product.ID != exceptID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MemoryProductRepo) Create(product *models.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if product.ID == "" {
+		product.ID = newID()
+	}
+	now := time.Now()
+	product.CreatedAt, product.UpdatedAt = now, now
+
+	stored := *product
+	stored.Variants = nil
+	r.products = append(r.products, stored)
+	return nil
+}
+
+func (r *MemoryProductRepo) CreateVariants(variants []models.ProductVariant) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range variants {
+		if variants[i].ID == "" {
+			variants[i].ID = newID()
+		}
+		now := time.Now()
+		variants[i].CreatedAt, variants[i].UpdatedAt = now, now
+		r.variants = append(r.variants, variants[i])
+	}
+	return nil
+}
+
+func (r *MemoryProductRepo) Update(product *models.Product) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(product.ID)
+	if i < 0 {
+		return nil
+	}
+
+	stored := *product
+	stored.Variants = nil
+	stored.Stock = r.products[i].Stock
+	stored.SKU = r.products[i].SKU
+	stored.AutoDisabled = r.products[i].AutoDisabled
+	stored.CreatedAt = r.products[i].CreatedAt
+	stored.UpdatedAt = time.Now()
+	r.products[i] = stored
+	return nil
+}
+
+func (r *MemoryProductRepo) SetSKU(id string, sku *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(id); i >= 0 {
+		r.products[i].SKU = sku
+	}
+	return nil
+}
+
+func (r *MemoryProductRepo) SetAvailability(id string, available bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(id); i >= 0 {
+		r.products[i].IsAvailable = available
+		r.products[i].AutoDisabled = false
+	}
+	return nil
+}
+
+func (r *MemoryProductRepo) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.products = append(r.products[:i], r.products[i+1:]...)
+
+	variants := r.variants[:0]
+	for _, variant := range r.variants {
+		if variant.ProductID != id {
+			variants = append(variants, variant)
+		}
+	}
+	r.variants = variants
+	return nil
+}
+
+// ==================== ORDERS ====================
+
+// MemoryOrderRepo is an in-memory OrderRepo
+type MemoryOrderRepo struct {
+	mu     sync.Mutex
+	orders []models.Order
+	items  []models.OrderItem
+}
+
+// NewMemoryOrderRepo returns an empty OrderRepo
+func NewMemoryOrderRepo() *MemoryOrderRepo {
+	return &MemoryOrderRepo{}
+}
+
+func (r *MemoryOrderRepo) index(id string) int {
+	for i := range r.orders {
+		if r.orders[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *MemoryOrderRepo) itemsOf(orderID string) []models.OrderItem {
+	var items []models.OrderItem
+	for _, item := range r.items {
+		if item.OrderID == orderID {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (r *MemoryOrderRepo) Create(order *models.Order, items []models.OrderItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if order.ID == "" {
+		order.ID = newID()
+	}
+	now := time.Now()
+	order.CreatedAt, order.UpdatedAt = now, now
+	r.orders = append(r.orders, *order)
+
+	for i := range items {
+		items[i].ID = newID()
+		items[i].OrderID = order.ID
+		items[i].CreatedAt = now
+		r.items = append(r.items, items[i])
+	}
+	return nil
+}
+
+func (r *MemoryOrderRepo) List() ([]models.OrderWithItems, error) {
+	return r.ListByCustomer("", "")
+}
+
+// An empty email lists every order
+func (r *MemoryOrderRepo) ListByCustomer(email, phone string) ([]models.OrderWithItems, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ordersWithItems := []models.OrderWithItems{}
+	for i := len(r.orders) - 1; i >= 0; i-- {
+		order := r.orders[i]
+		if email != "" && order.CustomerEmail != email {
+			continue
+		}
+		if phone != "" && order.CustomerPhone != phone {
+			continue
+		}
+		ordersWithItems = append(ordersWithItems, models.OrderWithItems{
+			Order: order,
+			Items: r.itemsOf(order.ID),
+		})
+	}
+	return ordersWithItems, nil
+}
+
+func (r *MemoryOrderRepo) Find(id string) (*models.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	order := r.orders[i]
+	return &order, nil
+}
+
+func (r *MemoryOrderRepo) Items(orderID string) ([]models.OrderItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.itemsOf(orderID), nil
+}
+
+func (r *MemoryOrderRepo) CountByStatus(status string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, order := range r.orders {
+		if order.OrderStatus == status {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *MemoryOrderRepo) SaveStatus(order *models.Order, oldStatus, oldPaymentStatus string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(order.ID)
+	if i < 0 || r.orders[i].OrderStatus != oldStatus || r.orders[i].PaymentStatus != oldPaymentStatus {
+		return ErrConflict
+	}
+	stored := &r.orders[i]
+	stored.OrderStatus = order.OrderStatus
+	stored.PaymentStatus = order.PaymentStatus
+	stored.CancellationReason = order.CancellationReason
+	stored.CancelledAt = order.CancelledAt
+	stored.DeliveryPhoto = order.DeliveryPhoto
+	stored.AppreciationMessage = order.AppreciationMessage
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+func (r *MemoryOrderRepo) DeleteCancelledBefore(t time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	orders := r.orders[:0]
+	for _, order := range r.orders {
+		if models.IsCancelledStatus(order.OrderStatus) && order.CancelledAt != nil && order.CancelledAt.Before(t) {
+			deleted++
+			continue
+		}
+		orders = append(orders, order)
+	}
+	r.orders = orders
+	return deleted, nil
+}
+
+func (r *MemoryOrderRepo) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(id)
+	if i < 0 {
+		return ErrNotFound
+	}
+	r.orders = append(r.orders[:i], r.orders[i+1:]...)
+
+	items := r.items[:0]
+	for _, item := range r.items {
+		if item.OrderID != id {
+			items = append(items, item)
+		}
+	}
+	r.items = items
+	return nil
+}
+
+// ==================== EVENTS ====================
+
+// MemoryEventRepo is an in-memory EventRepo
+type MemoryEventRepo struct {
+	mu       sync.Mutex
+	events   []models.Event
+	comments []models.EventComment
+}
+
+// NewMemoryEventRepo returns an EventRepo holding events
+func NewMemoryEventRepo(events ...models.Event) *MemoryEventRepo {
+	r := &MemoryEventRepo{}
+	for i := range events {
+		r.Create(&events[i])
+	}
+	return r
+}
+
+func (r *MemoryEventRepo) index(id string) int {
+	for i := range r.events {
+		if r.events[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *MemoryEventRepo) countComments(eventID string) int {
+	count := 0
+	for _, comment := range r.comments {
+		if comment.EventID == eventID {
+			count++
+		}
+	}
+	return count
+}
+
+func (r *MemoryEventRepo) list(activeOnly bool) []models.EventWithComments {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	eventsWithCount := []models.EventWithComments{}
+	for i := len(r.events) - 1; i >= 0; i-- {
+		event := r.events[i]
+		if activeOnly && !event.IsActive {
+			continue
+		}
+		eventsWithCount = append(eventsWithCount, models.EventWithComments{
+			Event:        event,
+			CommentCount: r.countComments(event.ID),
+		})
+	}
+	return eventsWithCount
+}
+
+func (r *MemoryEventRepo) ListActive() ([]models.EventWithComments, error) {
+	return r.list(true), nil
+}
+
+func (r *MemoryEventRepo) List() ([]models.EventWithComments, error) {
+	return r.list(false), nil
+}
+
+func (r *MemoryEventRepo) FindActive(id string) (*models.Event, error) {
+	event, err := r.Find(id)
+	if err != nil {
+		return nil, err
+	}
+	if !event.IsActive {
+		return nil, ErrNotFound
+	}
+	return event, nil
+}
+
+func (r *MemoryEventRepo) Find(id string) (*models.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	i := r.index(id)
+	if i < 0 {
+		return nil, ErrNotFound
+	}
+	event := r.events[i]
+	return &event, nil
+}
+
+func (r *MemoryEventRepo) Create(event *models.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if event.ID == "" {
+		event.ID = newID()
+	}
+	now := time.Now()
+	event.CreatedAt, event.UpdatedAt = now, now
+	r.events = append(r.events, *event)
+	return nil
+}
+
+// Like GORM, zero fields of changes are left alone
+func (r *MemoryEventRepo) Update(event *models.Event, changes models.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if changes.Title != "" {
+		event.Title = changes.Title
+	}
+	if changes.Description != "" {
+		event.Description = changes.Description
+	}
+	if changes.ImageURL != "" {
+		event.ImageURL = changes.ImageURL
+	}
+	if changes.MusicURL != "" {
+		event.MusicURL = changes.MusicURL
+	}
+	if changes.MusicTitle != "" {
+		event.MusicTitle = changes.MusicTitle
+	}
+	if changes.IsActive {
+		event.IsActive = true
+	}
+	event.UpdatedAt = time.Now()
+
+	if i := r.index(event.ID); i >= 0 {
+		r.events[i] = *event
+	}
+	return nil
+}
+
+func (r *MemoryEventRepo) Delete(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i := r.index(id); i >= 0 {
+		r.events = append(r.events[:i], r.events[i+1:]...)
+	}
+
+	comments := r.comments[:0]
+	for _, comment := range r.comments {
+		if comment.EventID != id {
+			comments = append(comments, comment)
+		}
+	}
+	r.comments = comments
+	return nil
+}
+
+func (r *MemoryEventRepo) Comments(eventID string) ([]models.EventComment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var comments []models.EventComment
+	for i := len(r.comments) - 1; i >= 0; i-- {
+		comment := r.comments[i]
+		if comment.EventID == eventID && comment.ParentID == nil {
+			comments = append(comments, comment)
+		}
+	}
+	return comments, nil
+}
+
+func (r *MemoryEventRepo) CountComments(eventID string) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return int64(r.countComments(eventID)), nil
+}
+
+func (r *MemoryEventRepo) Replies(commentID string) ([]models.EventComment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	replies := []models.EventComment{}
+	for _, comment := range r.comments {
+		if comment.ParentID != nil && *comment.ParentID == commentID {
+			replies = append(replies, comment)
+		}
+	}
+	return replies, nil
+}
+
+func (r *MemoryEventRepo) AddComment(comment *models.EventComment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	comment.ID = newID()
+	comment.EventID = strings.Clone(comment.EventID)
+	now := time.Now()
+	comment.CreatedAt, comment.UpdatedAt = now, now
+	r.comments = append(r.comments, *comment)
+	return nil
+}
+
+func (r *MemoryEventRepo) DeleteComment(id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	comments := r.comments[:0]
+	for _, comment := range r.comments {
+		if comment.ID != id {
+			comments = append(comments, comment)
+		}
+	}
+	r.comments = comments
+	return nil
+}
+
+// ==================== SETTINGS ====================
+
+// MemorySettingsRepo is an in-memory SettingsRepo
+type MemorySettingsRepo struct {
+	mu       sync.Mutex
+	settings map[string]models.Setting
+}
+
+// NewMemorySettingsRepo returns a SettingsRepo holding values by key
+func NewMemorySettingsRepo(values map[string]string) *MemorySettingsRepo {
+	r := &MemorySettingsRepo{settings: make(map[string]models.Setting)}
+	for key, value := range values {
+		r.Set(key, value)
+	}
+	return r
+}
+
+func (r *MemorySettingsRepo) Get(key string) (*models.Setting, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	setting, ok := r.settings[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &setting, nil
+}
+
+func (r *MemorySettingsRepo) Set(key, value string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	setting, ok := r.settings[key]
+	if !ok {
+		setting = models.Setting{ID: newID(), Key: strings.Clone(key), CreatedAt: now}
+	}
+	setting.Value = value
+	setting.UpdatedAt = now
+	r.settings[setting.Key] = setting
+	return nil
+}
@@ -0,0 +1,97 @@
+// Package repository is the data access of products, orders, events and
+// settings. Handlers depend on the interfaces here; the GORM implementations
+// talk to Postgres and the in-memory ones stand in for it in tests.
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"scaff-food-backend/internal/models"
+)
+
+// ErrNotFound is returned when the requested row does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrUnavailable is returned while the database is not connected
+var ErrUnavailable = errors.New("database not connected")
+
+// ErrConflict is returned when a row changed since it was read
+var ErrConflict = errors.New("changed concurrently")
+
+// ProductRepo stores products and their variants. Products are returned with
+// their variants loaded.
+type ProductRepo interface {
+	// First returns any product, used to check the table columns
+	First() (*models.Product, error)
+	// ListAvailable lists the products shown in the catalogue
+	ListAvailable() ([]models.Product, error)
+	// List lists every product, newest first
+	List() ([]models.Product, error)
+	// FindAvailable finds a product shown in the catalogue
+	FindAvailable(id string) (*models.Product, error)
+	Find(id string) (*models.Product, error)
+	// SKUTaken reports whether a product other than exceptID has the SKU
+	SKUTaken(sku, exceptID string) (bool, error)
+	Create(product *models.Product) error
+	CreateVariants(variants []models.ProductVariant) error
+	// Update saves the editable fields of a product. Stock and SKU have their
+	// own flows and are left alone.
+	Update(product *models.Product) error
+	SetSKU(id string, sku *string) error
+	// SetAvailability switches a product on or off by hand, clearing the
+	// automatic sold out flag
+	SetAvailability(id string, available bool) error
+	Delete(id string) error
+}
+
+// OrderRepo stores orders and their items
+type OrderRepo interface {
+	// Create stores an order and its items together
+	Create(order *models.Order, items []models.OrderItem) error
+	// List lists every order with its items, newest first
+	List() ([]models.OrderWithItems, error)
+	// ListByCustomer lists the orders placed with an email, newest first. A
+	// non-empty phone narrows them to that phone number.
+	ListByCustomer(email, phone string) ([]models.OrderWithItems, error)
+	Find(id string) (*models.Order, error)
+	Items(orderID string) ([]models.OrderItem, error)
+	CountByStatus(status string) (int64, error)
+	// SaveStatus saves the status, payment, cancellation and completion
+	// fields of an order, only if it still has oldStatus and
+	// oldPaymentStatus. Otherwise nothing is written and ErrConflict returned,
+	// so stock and notifications follow each change exactly once.
+	SaveStatus(order *models.Order, oldStatus, oldPaymentStatus string) error
+	// DeleteCancelledBefore deletes orders cancelled before t and returns how
+	// many were deleted
+	DeleteCancelledBefore(t time.Time) (int64, error)
+	Delete(id string) error
+}
+
+// EventRepo stores events and their comment threads
+type EventRepo interface {
+	// ListActive lists the public events with their comment counts, newest first
+	ListActive() ([]models.EventWithComments, error)
+	// List lists every event with its comment count, newest first
+	List() ([]models.EventWithComments, error)
+	FindActive(id string) (*models.Event, error)
+	Find(id string) (*models.Event, error)
+	Create(event *models.Event) error
+	// Update applies the non-zero fields of changes to event
+	Update(event *models.Event, changes models.Event) error
+	Delete(id string) error
+	// Comments lists the top-level comments of an event, newest first
+	Comments(eventID string) ([]models.EventComment, error)
+	CountComments(eventID string) (int64, error)
+	// Replies lists the replies to a comment, oldest first
+	Replies(commentID string) ([]models.EventComment, error)
+	AddComment(comment *models.EventComment) error
+	DeleteComment(id string) error
+}
+
+// SettingsRepo stores the key/value settings
+type SettingsRepo interface {
+	Get(key string) (*models.Setting, error)
+	// Set creates or overwrites a setting
+	Set(key, value string) error
+}
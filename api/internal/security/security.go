@@ -0,0 +1,201 @@
+// Package security detects malicious input and keeps the IP blacklist used
+// for DDoS and brute force protection.
+package security
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IPBlacklist for DDoS protection
+type IPBlacklist struct {
+	mu       sync.RWMutex
+	ips      map[string]time.Time
+	attempts map[string]int
+}
+
+// Blacklist is shared by the middleware and every handler that tracks abuse
+var Blacklist = NewIPBlacklist()
+
+// NewIPBlacklist returns an empty blacklist
+func NewIPBlacklist() *IPBlacklist {
+	return &IPBlacklist{
+		ips:      make(map[string]time.Time),
+		attempts: make(map[string]int),
+	}
+}
+
+// Add IP to blacklist
+func (bl *IPBlacklist) Add(ip string, duration time.Duration) {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+	bl.ips[ip] = time.Now().Add(duration)
+	log.Printf("🚫 IP %s blacklisted for %v", ip, duration)
+}
+
+// IsBlacklisted checks if IP is blacklisted
+func (bl *IPBlacklist) IsBlacklisted(ip string) bool {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	if expiry, exists := bl.ips[ip]; exists {
+		if time.Now().Before(expiry) {
+			return true
+		}
+		// Remove expired entry
+		delete(bl.ips, ip)
+	}
+	return false
+}
+
+// TrackAttempt tracks failed attempts, 10 of them blacklist the IP for an hour
+func (bl *IPBlacklist) TrackAttempt(ip string) int {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	bl.attempts[ip]++
+	count := bl.attempts[ip]
+
+	if count >= 10 {
+		bl.ips[ip] = time.Now().Add(1 * time.Hour)
+		bl.attempts[ip] = 0
+		log.Printf("🚫 IP %s auto-blacklisted after %d attempts", ip, count)
+	}
+
+	return count
+}
+
+// ResetAttempts on success
+func (bl *IPBlacklist) ResetAttempts(ip string) {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+	delete(bl.attempts, ip)
+}
+
+// CleanExpired entries, called periodically
+func (bl *IPBlacklist) CleanExpired() {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+
+	now := time.Now()
+	for ip, expiry := range bl.ips {
+		if now.After(expiry) {
+			delete(bl.ips, ip)
+			delete(bl.attempts, ip)
+		}
+	}
+}
+
+var (
+	sqlInjectionPatterns = compile(
+		`(?i)\b(UNION|SELECT|INSERT|UPDATE|DELETE|DROP|CREATE|ALTER|EXEC|EXECUTE)\b`,
+		`--`,
+		`;.*--`,
+		`/\*.*\*/`,
+		`xp_`,
+		`sp_`,
+		`0x[0-9a-f]+`,
+		`\bOR\b.*=.*`,
+		`\bAND\b.*=.*`,
+		`'.*OR.*'.*=.*'`,
+	)
+	xssPatterns = compile(
+		`<script[^>]*>.*</script>`,
+		`javascript:`,
+		`onerror\s*=`,
+		`onload\s*=`,
+		`onclick\s*=`,
+		`<iframe`,
+		`<embed`,
+		`<object`,
+		`eval\(`,
+		`alert\(`,
+		`document\.cookie`,
+		`window\.location`,
+	)
+	pathTraversalPatterns = compile(
+		`\.\.\/`,
+		`\.\.\\`,
+		`%2e%2e`,
+		`%252e%252e`,
+		`..;`,
+	)
+	commandInjectionPatterns = compile(
+		`[;&|]\s*(ls|cat|wget|curl|nc|bash|sh|cmd|powershell)`,
+		`\$\(.*\)`,
+		"`.*`",
+		`>\s*/dev/`,
+	)
+)
+
+func compile(patterns ...string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, pattern := range patterns {
+		compiled[i] = regexp.MustCompile(pattern)
+	}
+	return compiled
+}
+
+func matchAny(patterns []*regexp.Regexp, input string) bool {
+	for _, re := range patterns {
+		if re.MatchString(input) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetectSQLInjection detects SQL injection attempts
+func DetectSQLInjection(input string) bool {
+	if matchAny(sqlInjectionPatterns, input) {
+		log.Printf("🚨 SQL Injection attempt detected: %s", input)
+		return true
+	}
+	return false
+}
+
+// DetectXSS detects XSS attempts
+func DetectXSS(input string) bool {
+	if matchAny(xssPatterns, strings.ToLower(input)) {
+		log.Printf("🚨 XSS attempt detected: %s", input)
+		return true
+	}
+	return false
+}
+
+// DetectPathTraversal detects path traversal attempts
+func DetectPathTraversal(input string) bool {
+	if matchAny(pathTraversalPatterns, strings.ToLower(input)) {
+		log.Printf("🚨 Path traversal attempt detected: %s", input)
+		return true
+	}
+	return false
+}
+
+// DetectCommandInjection detects shell command injection
+func DetectCommandInjection(input string) bool {
+	if matchAny(commandInjectionPatterns, input) {
+		log.Printf("🚨 Command injection attempt detected: %s", input)
+		return true
+	}
+	return false
+}
+
+// IsThreat runs every detector on the input
+func IsThreat(input string) bool {
+	return DetectSQLInjection(input) ||
+		DetectXSS(input) ||
+		DetectPathTraversal(input) ||
+		DetectCommandInjection(input)
+}
+
+// HashData hashes sensitive data
+func HashData(data string) string {
+	hash := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,91 @@
+// Package settings stores the key/value settings edited from the admin panel.
+package settings
+
+import (
+	"errors"
+	"log"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Handler serves the settings of a SettingsRepo
+type Handler struct {
+	repo repository.SettingsRepo
+}
+
+// NewHandler returns a Handler reading and writing settings through repo
+func NewHandler(repo repository.SettingsRepo) *Handler {
+	return &Handler{repo: repo}
+}
+
+// Answer with status and message, or 503 while the database is down
+func fail(c *fiber.Ctx, err error, status int, message string) error {
+	if errors.Is(err, repository.ErrUnavailable) {
+		status, message = 503, "Database not connected"
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"success": false,
+		"message": message,
+	})
+}
+
+// RegisterRoutes mounts reading a setting by key and updating it as an admin
+func (h *Handler) RegisterRoutes(app fiber.Router) {
+	// Get setting by key
+	app.Get("/api/settings", func(c *fiber.Ctx) error {
+		key := c.Query("key")
+		if key == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Key parameter is required",
+			})
+		}
+
+		setting, err := h.repo.Get(key)
+		if err != nil {
+			return fail(c, err, 404, "Setting not found")
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    setting,
+		})
+	})
+
+	// Update setting (admin)
+	app.Put("/api/settings", auth.RequireSession, auth.RequireAdmin, func(c *fiber.Ctx) error {
+		var requestData struct {
+			Key   string `json:"key"`
+			Value string `json:"value"`
+		}
+
+		if err := c.BodyParser(&requestData); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+
+		if requestData.Key == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Key is required",
+			})
+		}
+
+		// Upsert setting
+		if err := h.repo.Set(requestData.Key, requestData.Value); err != nil {
+			return fail(c, err, 500, "Failed to update setting")
+		}
+
+		log.Printf("✅ Setting updated: %s", requestData.Key)
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Setting updated successfully",
+		})
+	})
+}
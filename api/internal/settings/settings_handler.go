@@ -1,124 +0,0 @@
-package settings
-
-import (
-	"database/sql"
-	"encoding/json"
-	"net/http"
-	"time"
-)
-
-type SettingsHandler struct {
-	DB *sql.DB
-}
-
-type Setting struct {
-	ID          string    `json:"id"`
-	Key         string    `json:"key"`
-	Value       string    `json:"value"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-}
-
-func NewSettingsHandler(db *sql.DB) *SettingsHandler {
-	return &SettingsHandler{DB: db}
-}
-
-// Get setting by key
-func (h *SettingsHandler) GetSetting(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	if r.Method == "OPTIONS" {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-
-	key := r.URL.Query().Get("key")
-	if key == "" {
-		http.Error(w, "Key parameter is required", http.StatusBadRequest)
-		return
-	}
-
-	var setting Setting
-	query := `SELECT id, key, value, description, created_at, updated_at FROM settings WHERE key = $1`
-	err := h.DB.QueryRow(query, key).Scan(
-		&setting.ID,
-		&setting.Key,
-		&setting.Value,
-		&setting.Description,
-		&setting.CreatedAt,
-		&setting.UpdatedAt,
-	)
-
-	if err == sql.ErrNoRows {
-		http.Error(w, "Setting not found", http.StatusNotFound)
-		return
-	}
-
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(setting)
-}
-
-// Update setting
-func (h *SettingsHandler) UpdateSetting(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.Header().Set("Access-Control-Allow-Methods", "PUT, OPTIONS")
-	w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
-
-	if r.Method == "OPTIONS" {
-		w.WriteHeader(http.StatusOK)
-		return
-	}
-
-	var requestData struct {
-		Key   string `json:"key"`
-		Value string `json:"value"`
-	}
-
-	if err := json.NewDecoder(r.Body).Decode(&requestData); err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
-	}
-
-	if requestData.Key == "" {
-		http.Error(w, "Key is required", http.StatusBadRequest)
-		return
-	}
-
-	query := `
-		INSERT INTO settings (key, value, updated_at) 
-		VALUES ($1, $2, CURRENT_TIMESTAMP)
-		ON CONFLICT (key) 
-		DO UPDATE SET value = $2, updated_at = CURRENT_TIMESTAMP
-		RETURNING id, key, value, description, created_at, updated_at
-	`
-
-	var setting Setting
-	err := h.DB.QueryRow(query, requestData.Key, requestData.Value).Scan(
-		&setting.ID,
-		&setting.Key,
-		&setting.Value,
-		&setting.Description,
-		&setting.CreatedAt,
-		&setting.UpdatedAt,
-	)
-
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"success": true,
-		"message": "Setting updated successfully",
-		"data":    setting,
-	})
-}
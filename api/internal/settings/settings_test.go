@@ -0,0 +1,115 @@
+package settings
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/repository"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func newApp(repo repository.SettingsRepo) *fiber.App {
+	app := fiber.New()
+	NewHandler(repo).RegisterRoutes(app)
+	return app
+}
+
+var adminToken = auth.Sessions.Create("admin-1", "admin@example.com", auth.RoleAdmin, time.Hour)
+
+func do(t *testing.T, app *fiber.App, method, target, token, body string) (int, map[string]interface{}) {
+	t.Helper()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, target, err)
+	}
+	var payload map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("%s %s: decode body: %v", method, target, err)
+	}
+	return resp.StatusCode, payload
+}
+
+func TestGetSetting(t *testing.T) {
+	app := newApp(repository.NewMemorySettingsRepo(map[string]string{"whatsapp": "6281234567890"}))
+
+	status, payload := do(t, app, "GET", "/api/settings?key=whatsapp", "", "")
+	if status != 200 {
+		t.Fatalf("status = %d, want 200", status)
+	}
+	data := payload["data"].(map[string]interface{})
+	if data["value"] != "6281234567890" {
+		t.Errorf("value = %v", data["value"])
+	}
+}
+
+func TestGetSettingErrors(t *testing.T) {
+	app := newApp(repository.NewMemorySettingsRepo(nil))
+
+	if status, _ := do(t, app, "GET", "/api/settings", "", ""); status != 400 {
+		t.Errorf("missing key: status = %d, want 400", status)
+	}
+	if status, _ := do(t, app, "GET", "/api/settings?key=missing", "", ""); status != 404 {
+		t.Errorf("unknown key: status = %d, want 404", status)
+	}
+}
+
+func TestUpdateSettingUpserts(t *testing.T) {
+	repo := repository.NewMemorySettingsRepo(map[string]string{"banner": "old"})
+	app := newApp(repo)
+
+	for _, body := range []string{`{"key":"banner","value":"new"}`, `{"key":"footer","value":"hello"}`} {
+		if status, payload := do(t, app, "PUT", "/api/settings", adminToken, body); status != 200 {
+			t.Fatalf("PUT %s: status = %d, body = %v", body, status, payload)
+		}
+	}
+
+	for key, want := range map[string]string{"banner": "new", "footer": "hello"} {
+		setting, err := repo.Get(key)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", key, err)
+		}
+		if setting.Value != want {
+			t.Errorf("%s = %q, want %q", key, setting.Value, want)
+		}
+	}
+
+	if status, _ := do(t, app, "PUT", "/api/settings", adminToken, `{"value":"x"}`); status != 400 {
+		t.Errorf("missing key: status = %d, want 400", status)
+	}
+
+	customer := auth.Sessions.Create("user-1", "a@example.com", auth.RoleCustomer, time.Hour)
+	for token, want := range map[string]int{"": 401, customer: 403} {
+		if status, _ := do(t, app, "PUT", "/api/settings", token, `{"key":"banner","value":"hacked"}`); status != want {
+			t.Errorf("token %q: status = %d, want %d", token, status, want)
+		}
+	}
+}
+
+func TestDatabaseDownAnswers503(t *testing.T) {
+	app := newApp(repository.NewGormSettingsRepo(func() *gorm.DB { return nil }))
+
+	status, payload := do(t, app, "GET", "/api/settings?key=whatsapp", "", "")
+	if status != 503 {
+		t.Fatalf("status = %d, want 503", status)
+	}
+	if payload["message"] != "Database not connected" {
+		t.Errorf("message = %v", payload["message"])
+	}
+}
@@ -0,0 +1,166 @@
+// Package uploads stores uploaded images under public/produk and serves them.
+package uploads
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"scaff-food-backend/internal/security"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Image types accepted for upload
+var imageExts = []string{".jpg", ".jpeg", ".png", ".gif", ".webp"}
+
+// Uploads larger than this are rejected
+const maxUploadSize = 5 * 1024 * 1024
+
+// PublicDir resolves the public directory for uploads both in local dev and
+// Docker/production
+func PublicDir() string {
+	// Try ./public relative to current working directory
+	if _, err := os.Stat("public"); err == nil {
+		return "public"
+	}
+
+	// Try ../public (when binary is run from api/ directory)
+	if _, err := os.Stat(filepath.Join("..", "public")); err == nil {
+		return filepath.Join("..", "public")
+	}
+
+	// Fallback to ./public
+	return "public"
+}
+
+// CheckWritable creates and removes a file in the upload directory
+func CheckWritable() error {
+	uploadDir := filepath.Join(PublicDir(), "produk")
+	if err := os.MkdirAll(uploadDir, 0755); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(uploadDir, ".writable-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+func isImageFile(filename string) bool {
+	ext := strings.ToLower(filepath.Ext(filename))
+	for _, validExt := range imageExts {
+		if ext == validExt {
+			return true
+		}
+	}
+	return false
+}
+
+func randomString(length int) string {
+	const charset = "abcdefghijklmnopqrstuvwxyz0123456789"
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = charset[rand.Intn(len(charset))]
+	}
+	return string(b)
+}
+
+// ValidateFile checks the extension, size and name of an uploaded file
+func ValidateFile(filename string, fileSize int64) error {
+	if !isImageFile(filename) {
+		return fmt.Errorf("file type not allowed")
+	}
+
+	if fileSize > maxUploadSize {
+		return fmt.Errorf("file size exceeds 5MB limit")
+	}
+
+	// Check for double extensions (e.g., file.php.jpg)
+	if strings.Count(filename, ".") > 1 {
+		return fmt.Errorf("suspicious filename detected")
+	}
+
+	return nil
+}
+
+// RegisterRoutes mounts the image upload endpoint and the static files it writes
+func RegisterRoutes(app *fiber.App) {
+	// Static files for uploaded images (QRIS, product images, etc.)
+	app.Static("/produk", filepath.Join(PublicDir(), "produk"))
+
+	// Upload image endpoint with enhanced security
+	app.Post("/api/upload", func(c *fiber.Ctx) error {
+		// Get file from form
+		file, err := c.FormFile("image")
+		if err != nil {
+			log.Printf("Error getting file: %v", err)
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "No file uploaded",
+			})
+		}
+
+		// Security validation
+		if err := ValidateFile(file.Filename, file.Size); err != nil {
+			ip := c.IP()
+			security.Blacklist.TrackAttempt(ip)
+			log.Printf("🚨 Invalid file upload attempt from IP %s: %v", ip, err)
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": err.Error(),
+			})
+		}
+
+		// Sanitize filename to prevent path traversal
+		originalFilename := filepath.Base(file.Filename)
+		if security.DetectPathTraversal(originalFilename) {
+			ip := c.IP()
+			security.Blacklist.Add(ip, 24*time.Hour)
+			log.Printf("🚨 Path traversal attempt in filename from IP %s: %s", ip, originalFilename)
+			return c.Status(403).JSON(fiber.Map{
+				"success": false,
+				"message": "Malicious filename detected",
+			})
+		}
+
+		// Generate unique, safe filename
+		ext := filepath.Ext(originalFilename)
+		filename := fmt.Sprintf("%d_%s%s", time.Now().Unix(), randomString(8), ext)
+
+		// Resolve base public directory and ensure upload folder exists
+		uploadDir := filepath.Join(PublicDir(), "produk")
+		if err := os.MkdirAll(uploadDir, 0755); err != nil {
+			log.Printf("Error creating upload directory %s: %v", uploadDir, err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to prepare upload directory",
+			})
+		}
+
+		// Save to public/produk directory
+		uploadPath := filepath.Join(uploadDir, filename)
+		if err := c.SaveFile(file, uploadPath); err != nil {
+			log.Printf("Error saving file to %s: %v", uploadPath, err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to save file",
+			})
+		}
+
+		// Return URL
+		imageURL := fmt.Sprintf("/produk/%s", filename)
+		log.Printf("✅ Image uploaded successfully: %s (from IP: %s)", imageURL, c.IP())
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"url":     imageURL,
+			"message": "Image uploaded successfully",
+		})
+	})
+}
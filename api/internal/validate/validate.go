@@ -0,0 +1,91 @@
+// Package validate sanitizes and validates user input: free text, emails,
+// Indonesian phone numbers and ids.
+package validate
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	htmlTagRegex = regexp.MustCompile(`<[^>]*>`)
+	emailRegex   = regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
+	uuidRegex    = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+	// Indonesian phone number: +62, 62 or 0 prefix followed by 9-12 digits
+	phoneRegex = regexp.MustCompile(`^(\+62|62|0)[0-9]{9,12}$`)
+
+	// Removed from free text by SanitizeString
+	sqlPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)(\bUNION\b|\bSELECT\b|\bINSERT\b|\bUPDATE\b|\bDELETE\b|\bDROP\b|\bCREATE\b|\bALTER\b)`),
+		regexp.MustCompile(`--`),
+		regexp.MustCompile(`;`),
+		regexp.MustCompile(`/\*`),
+		regexp.MustCompile(`\*/`),
+		regexp.MustCompile(`xp_`),
+		regexp.MustCompile(`sp_`),
+	}
+)
+
+// SanitizeString strips HTML tags and SQL injection patterns from free text
+func SanitizeString(input string) string {
+	cleaned := htmlTagRegex.ReplaceAllString(input, "")
+	for _, re := range sqlPatterns {
+		cleaned = re.ReplaceAllString(cleaned, "")
+	}
+	return strings.TrimSpace(cleaned)
+}
+
+// IsValidEmail validates the email format
+func IsValidEmail(email string) bool {
+	return emailRegex.MatchString(email)
+}
+
+// NormalizeEmail normalizes an email for storage and lookups
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// CleanPhone removes common separators from a phone number
+func CleanPhone(phone string) string {
+	cleaned := strings.TrimSpace(phone)
+	cleaned = strings.ReplaceAll(cleaned, " ", "")
+	cleaned = strings.ReplaceAll(cleaned, "-", "")
+	cleaned = strings.ReplaceAll(cleaned, ".", "")
+	cleaned = strings.ReplaceAll(cleaned, "(", "")
+	cleaned = strings.ReplaceAll(cleaned, ")", "")
+	return cleaned
+}
+
+// IsValidPhone validates a phone number (Indonesian format)
+func IsValidPhone(phone string) bool {
+	return phoneRegex.MatchString(CleanPhone(phone))
+}
+
+// NormalizePhone normalizes an Indonesian phone number to E.164 (+628xxxxxxxxx).
+// Migration 018 applies the same rule in SQL to existing rows.
+func NormalizePhone(phone string) (string, bool) {
+	cleaned := CleanPhone(phone)
+	if !phoneRegex.MatchString(cleaned) {
+		return "", false
+	}
+
+	var national string
+	switch {
+	case strings.HasPrefix(cleaned, "+62"):
+		national = cleaned[3:]
+	case strings.HasPrefix(cleaned, "62"):
+		national = cleaned[2:]
+	default:
+		national = cleaned[1:]
+	}
+	// +62 0812... is a common typo for +62 812...
+	national = strings.TrimPrefix(national, "0")
+
+	return "+62" + national, true
+}
+
+// IsValidUUID validates the UUID format
+func IsValidUUID(uuid string) bool {
+	return uuidRegex.MatchString(uuid)
+}
@@ -0,0 +1,247 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Event types a webhook can subscribe to
+const (
+	EventOrderCreated       = "order.created"
+	EventOrderStatusChanged = "order.status_changed"
+	EventPaymentUpdated     = "payment.updated"
+	EventProductStockLow    = "product.stock_low"
+	// Sent by the test endpoint regardless of subscriptions
+	EventTest = "webhook.test"
+)
+
+// EventTypes lists the event types a webhook can subscribe to
+var EventTypes = []string{
+	EventOrderCreated,
+	EventOrderStatusChanged,
+	EventPaymentUpdated,
+	EventProductStockLow,
+}
+
+// Payload formats
+const (
+	// Signed JSON envelope, for automations
+	FormatJSON = "json"
+	// Discord webhook message
+	FormatDiscord = "discord"
+)
+
+// Request headers sent with every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Webhook is a registered endpoint and the events it receives
+type Webhook struct {
+	ID        string         `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name      string         `gorm:"not null" json:"name"`
+	URL       string         `gorm:"not null" json:"url"`
+	Secret    string         `gorm:"not null" json:"secret,omitempty"`
+	Events    pq.StringArray `gorm:"type:text[]" json:"events"`
+	Format    string         `gorm:"default:'json'" json:"format"`
+	IsActive  bool           `gorm:"default:true" json:"is_active"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// Subscribed reports whether the webhook receives the event type
+func (w *Webhook) Subscribed(eventType string) bool {
+	if !w.IsActive {
+		return false
+	}
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEventType reports whether name is a known event type
+func IsEventType(name string) bool {
+	for _, e := range EventTypes {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrBlockedAddress is returned for endpoints on internal networks
+var ErrBlockedAddress = errors.New("webhook URL must point to a public address")
+
+// Shared address space (carrier-grade NAT), not covered by net.IP.IsPrivate
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Loopback, private, link-local (which holds the cloud metadata address)
+// and other non-public addresses must not be reachable through webhooks
+func isBlocked(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() ||
+		sharedAddressSpace.Contains(ip)
+}
+
+// ValidateURL accepts absolute http and https URLs whose host resolves to
+// public addresses only. Send checks the address again when it connects.
+func ValidateURL(ctx context.Context, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("URL must be an absolute http or https URL")
+	}
+
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("URL host could not be resolved")
+	}
+	for _, addr := range addrs {
+		if isBlocked(addr.IP) {
+			return ErrBlockedAddress
+		}
+	}
+	return nil
+}
+
+// Sign returns the hex HMAC-SHA256 of "timestamp.body" with the secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header value against the body, receivers can
+// use it as the reference implementation
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	expected := "sha256=" + Sign(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// Envelope is the JSON body of a FormatJSON delivery
+type Envelope struct {
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// Body encodes an event in the webhook's format, summary is the one line
+// text used by chat formats
+func Body(format string, env Envelope, summary string) ([]byte, error) {
+	if format == FormatDiscord {
+		return json.Marshal(map[string]string{
+			"username": "SCAFF*FOOD",
+			"content":  summary,
+		})
+	}
+	return json.Marshal(env)
+}
+
+// Delivery is one signed request to a webhook
+type Delivery struct {
+	ID    string
+	Event string
+	URL   string
+	Body  []byte
+}
+
+// StatusError is a non-2xx response. The response body is left out on
+// purpose, it is shown to admins.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("webhook responded %d", e.StatusCode)
+}
+
+// Sender posts deliveries
+type Sender struct {
+	client *http.Client
+}
+
+// Refuse the connection when the resolved address is not public, so a
+// host cannot pass ValidateURL and then resolve to an internal address
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || isBlocked(ip) {
+		return ErrBlockedAddress
+	}
+	return nil
+}
+
+func NewSender(timeout time.Duration) *Sender {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialControl}
+	return &Sender{client: &http.Client{
+		Timeout: timeout,
+		// No proxy, it would be dialed instead of the endpoint
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		// Redirects are not followed, they could point anywhere
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// Send posts the delivery signed with secret, any non-2xx response is an error
+func (s *Sender) Send(ctx context.Context, secret string, d Delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Body))
+	if err != nil {
+		return err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "SCAFF-FOOD-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, d.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, "sha256="+Sign(secret, timestamp, d.Body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &StatusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestValidateURL(t *testing.T) {
+	cases := []struct {
+		url     string
+		blocked bool
+		invalid bool
+	}{
+		{url: "https://93.184.216.34/hook"},
+		{url: "ftp://93.184.216.34/hook", invalid: true},
+		{url: "/relative", invalid: true},
+		{url: "http://127.0.0.1:8080/", blocked: true},
+		{url: "http://localhost/", blocked: true},
+		{url: "http://10.1.2.3/", blocked: true},
+		{url: "http://192.168.1.10/", blocked: true},
+		{url: "http://172.16.0.1/", blocked: true},
+		{url: "http://100.64.0.1/", blocked: true},
+		{url: "http://169.254.169.254/latest/meta-data/", blocked: true},
+		{url: "http://0.0.0.0/", blocked: true},
+		{url: "http://[::1]/", blocked: true},
+		{url: "http://[fd00::1]/", blocked: true},
+		{url: "http://[fe80::1]/", blocked: true},
+		{url: "http://[::ffff:127.0.0.1]/", blocked: true},
+	}
+	for _, tc := range cases {
+		err := ValidateURL(context.Background(), tc.url)
+		switch {
+		case tc.blocked && !errors.Is(err, ErrBlockedAddress):
+			t.Errorf("%s: err = %v, want ErrBlockedAddress", tc.url, err)
+		case tc.invalid && (err == nil || errors.Is(err, ErrBlockedAddress)):
+			t.Errorf("%s: err = %v, want invalid URL", tc.url, err)
+		case !tc.blocked && !tc.invalid && err != nil:
+			t.Errorf("%s: err = %v", tc.url, err)
+		}
+	}
+}
+
+func TestSendRefusesInternalAddressAtDial(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request reached a loopback server")
+	}))
+	defer server.Close()
+
+	err := NewSender(time.Second).Send(context.Background(), "secret", Delivery{URL: server.URL, Body: []byte("{}")})
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("err = %v, want ErrBlockedAddress", err)
+	}
+}
@@ -0,0 +1,251 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// ErrNoSheet is returned for workbooks without a worksheet
+var ErrNoSheet = errors.New("xlsx: workbook has no sheet")
+
+// ReadRows returns the cells of the first sheet as text, one slice per row.
+// Empty rows are kept so row numbers match the spreadsheet. At most maxRows
+// rows are read when maxRows > 0.
+func ReadRows(r io.ReaderAt, size int64, maxRows int) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("xlsx: not a spreadsheet: %w", err)
+	}
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	sheet, ok := files[sheetPath]
+	if !ok {
+		return nil, ErrNoSheet
+	}
+
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if shared, err = readSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+	return readSheet(sheet, shared, maxRows)
+}
+
+// Path of the first sheet listed in the workbook
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeFile(files["xl/workbook.xml"], &workbook); err != nil {
+		return "", err
+	}
+	if err := decodeFile(files["xl/_rels/workbook.xml.rels"], &rels); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", ErrNoSheet
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID == workbook.Sheets[0].RID {
+			if strings.HasPrefix(rel.Target, "/") {
+				return strings.TrimPrefix(rel.Target, "/"), nil
+			}
+			return path.Join("xl", rel.Target), nil
+		}
+	}
+	return "xl/worksheets/sheet1.xml", nil
+}
+
+func decodeFile(f *zip.File, v interface{}) error {
+	if f == nil {
+		return ErrNoSheet
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	return xml.NewDecoder(rc).Decode(v)
+}
+
+// Shared strings, rich text runs joined into plain text
+func readSharedStrings(f *zip.File) ([]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var shared []string
+	var current strings.Builder
+	inText := false
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return shared, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				current.Reset()
+			case "t":
+				inText = true
+			case "rPh":
+				// Phonetic hints are not part of the text
+				if err := dec.Skip(); err != nil {
+					return nil, err
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "si":
+				shared = append(shared, current.String())
+			case "t":
+				inText = false
+			}
+		case xml.CharData:
+			if inText {
+				current.Write(t)
+			}
+		}
+	}
+}
+
+func readSheet(f *zip.File, shared []string, maxRows int) ([][]string, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	var rows [][]string
+	var row []string
+	var cellType, cellRef string
+	var value strings.Builder
+	inValue := false
+	col := 0
+
+	dec := xml.NewDecoder(rc)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				row = nil
+				col = 0
+				// Rows may skip empty ones, pad to keep row numbers
+				if n, err := strconv.Atoi(attr(t, "r")); err == nil {
+					for len(rows) < n-1 {
+						rows = append(rows, nil)
+					}
+				}
+			case "c":
+				cellType, cellRef = attr(t, "t"), attr(t, "r")
+				value.Reset()
+			case "v", "t":
+				inValue = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "row":
+				rows = append(rows, row)
+				if maxRows > 0 && len(rows) >= maxRows {
+					return rows, nil
+				}
+			case "c":
+				if index, ok := columnIndex(cellRef); ok {
+					col = index
+				}
+				for len(row) < col {
+					row = append(row, "")
+				}
+				row = append(row, cellText(cellType, value.String(), shared))
+				col++
+			case "v", "t":
+				inValue = false
+			}
+		case xml.CharData:
+			if inValue {
+				value.Write(t)
+			}
+		}
+	}
+}
+
+func cellText(cellType, raw string, shared []string) string {
+	switch cellType {
+	case "s":
+		i, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || i < 0 || i >= len(shared) {
+			return ""
+		}
+		return shared[i]
+	case "b":
+		if strings.TrimSpace(raw) == "1" {
+			return "true"
+		}
+		return "false"
+	default:
+		return raw
+	}
+}
+
+func attr(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// Zero based column of a cell reference like "BC12"
+func columnIndex(ref string) (int, bool) {
+	index := 0
+	letters := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		index = index*26 + int(r-'A'+1)
+		letters++
+	}
+	if letters == 0 {
+		return 0, false
+	}
+	return index - 1, true
+}
@@ -0,0 +1,279 @@
+// Package xlsx writes spreadsheets in the Office Open XML format one row at
+// a time, so large exports never sit in memory.
+package xlsx
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cell styles, indexes into cellXfs of styles.xml
+const (
+	styleDefault  = 0
+	styleHeader   = 1
+	styleDateTime = 2
+	styleDate     = 3
+	styleDecimal  = 4
+)
+
+// Writer streams sheets into a workbook. Sheets are written one after the
+// other, starting a new sheet finishes the previous one.
+type Writer struct {
+	zip    *zip.Writer
+	sheets []string
+	sheet  *Sheet
+}
+
+// Sheet is the sheet currently being written
+type Sheet struct {
+	w    *bufio.Writer
+	rows int
+}
+
+// NewWriter starts a workbook written to out
+func NewWriter(out io.Writer) *Writer {
+	return &Writer{zip: zip.NewWriter(out)}
+}
+
+// NewSheet finishes the current sheet and starts a new one. header is
+// written as a bold first row that stays visible when scrolling.
+func (w *Writer) NewSheet(name string, header ...string) (*Sheet, error) {
+	if err := w.finishSheet(); err != nil {
+		return nil, err
+	}
+
+	w.sheets = append(w.sheets, sheetName(name, len(w.sheets)+1))
+	f, err := w.zip.Create(fmt.Sprintf("xl/worksheets/sheet%d.xml", len(w.sheets)))
+	if err != nil {
+		return nil, err
+	}
+	s := &Sheet{w: bufio.NewWriter(f)}
+	s.w.WriteString(xml.Header)
+	s.w.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	if len(header) > 0 {
+		s.w.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+	}
+	s.w.WriteString(`<sheetData>`)
+	w.sheet = s
+
+	if len(header) > 0 {
+		values := make([]interface{}, len(header))
+		for i, h := range header {
+			values[i] = h
+		}
+		if err := s.writeRow(values, styleHeader); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// WriteRow adds a row. Values may be strings, integers, floats, bools,
+// time.Time (a date when it has no time of day) or nil for an empty cell.
+func (s *Sheet) WriteRow(values ...interface{}) error {
+	return s.writeRow(values, styleDefault)
+}
+
+func (s *Sheet) writeRow(values []interface{}, style int) error {
+	s.rows++
+	fmt.Fprintf(s.w, `<row r="%d">`, s.rows)
+	for i, v := range values {
+		ref := columnName(i) + strconv.Itoa(s.rows)
+		s.writeCell(ref, v, style)
+	}
+	_, err := s.w.WriteString(`</row>`)
+	return err
+}
+
+func (s *Sheet) writeCell(ref string, v interface{}, style int) {
+	number := func(n string, numberStyle int) {
+		if style != styleDefault {
+			numberStyle = style
+		}
+		if numberStyle != styleDefault {
+			fmt.Fprintf(s.w, `<c r="%s" s="%d"><v>%s</v></c>`, ref, numberStyle, n)
+		} else {
+			fmt.Fprintf(s.w, `<c r="%s"><v>%s</v></c>`, ref, n)
+		}
+	}
+
+	switch val := v.(type) {
+	case nil:
+		return
+	case string:
+		s.writeString(ref, val, style)
+	case []byte:
+		s.writeString(ref, string(val), style)
+	case int:
+		number(strconv.Itoa(val), styleDefault)
+	case int32:
+		number(strconv.FormatInt(int64(val), 10), styleDefault)
+	case int64:
+		number(strconv.FormatInt(val, 10), styleDefault)
+	case float32:
+		number(strconv.FormatFloat(float64(val), 'f', -1, 32), styleDecimal)
+	case float64:
+		number(strconv.FormatFloat(val, 'f', -1, 64), styleDecimal)
+	case bool:
+		b := "0"
+		if val {
+			b = "1"
+		}
+		fmt.Fprintf(s.w, `<c r="%s" t="b"><v>%s</v></c>`, ref, b)
+	case time.Time:
+		if val.IsZero() {
+			return
+		}
+		dateStyle := styleDateTime
+		if val.Hour() == 0 && val.Minute() == 0 && val.Second() == 0 && val.Nanosecond() == 0 {
+			dateStyle = styleDate
+		}
+		number(strconv.FormatFloat(serialDate(val), 'f', -1, 64), dateStyle)
+	case *time.Time:
+		if val != nil {
+			s.writeCell(ref, *val, style)
+		}
+	default:
+		s.writeString(ref, fmt.Sprint(val), style)
+	}
+}
+
+func (s *Sheet) writeString(ref, val string, style int) {
+	if style != styleDefault {
+		fmt.Fprintf(s.w, `<c r="%s" t="inlineStr" s="%d"><is><t xml:space="preserve">`, ref, style)
+	} else {
+		fmt.Fprintf(s.w, `<c r="%s" t="inlineStr"><is><t xml:space="preserve">`, ref)
+	}
+	xml.EscapeText(s.w, []byte(val))
+	s.w.WriteString(`</t></is></c>`)
+}
+
+// Excel stores dates as days since 1899-12-30, in the wall clock time of t
+func serialDate(t time.Time) float64 {
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	return wall.Sub(epoch).Hours() / 24
+}
+
+// Column letters of a zero based index: A, B, ..., Z, AA, AB, ...
+func columnName(i int) string {
+	name := ""
+	for i >= 0 {
+		name = string(rune('A'+i%26)) + name
+		i = i/26 - 1
+	}
+	return name
+}
+
+// Sheet names are at most 31 characters without []:*?/\
+func sheetName(name string, n int) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if name == "" {
+		name = fmt.Sprintf("Sheet%d", n)
+	}
+	return name
+}
+
+func (w *Writer) finishSheet() error {
+	if w.sheet == nil {
+		return nil
+	}
+	w.sheet.w.WriteString(`</sheetData></worksheet>`)
+	err := w.sheet.w.Flush()
+	w.sheet = nil
+	return err
+}
+
+// Close finishes the last sheet and writes the workbook parts
+func (w *Writer) Close() error {
+	if err := w.finishSheet(); err != nil {
+		return err
+	}
+	if len(w.sheets) == 0 {
+		if _, err := w.NewSheet("Sheet1"); err != nil {
+			return err
+		}
+		if err := w.finishSheet(); err != nil {
+			return err
+		}
+	}
+
+	var contentTypes, workbook, workbookRels strings.Builder
+	contentTypes.WriteString(xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	workbook.WriteString(xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	workbookRels.WriteString(xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+
+	for i, name := range w.sheets {
+		n := i + 1
+		fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+		fmt.Fprintf(&workbook, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escapeAttr(name), n, n)
+		fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+	}
+	fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(w.sheets)+1)
+	contentTypes.WriteString(`</Types>`)
+	workbook.WriteString(`</sheets></workbook>`)
+	workbookRels.WriteString(`</Relationships>`)
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", contentTypes.String()},
+		{"_rels/.rels", rootRels},
+		{"xl/workbook.xml", workbook.String()},
+		{"xl/_rels/workbook.xml.rels", workbookRels.String()},
+		{"xl/styles.xml", styles},
+	}
+	for _, part := range parts {
+		f, err := w.zip.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, part.body); err != nil {
+			return err
+		}
+	}
+	return w.zip.Close()
+}
+
+func escapeAttr(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+const rootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const styles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="2"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm"/><numFmt numFmtId="165" formatCode="yyyy-mm-dd"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="5">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="165" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="4" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
@@ -0,0 +1,454 @@
+package main
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ==================== INVENTORY LEDGER ====================
+
+// Inventory movement types
+const (
+	MovementRestock      = "restock"
+	MovementSale         = "sale"
+	MovementCancelReturn = "cancel_return"
+	MovementWaste        = "waste"
+	MovementAdjust       = "adjust"
+)
+
+// InventoryAdjustRequest is the body of POST /api/admin/inventory/adjust
+type InventoryAdjustRequest struct {
+	ProductID string  `json:"product_id"`
+	VariantID *string `json:"variant_id"`
+	Type      string  `json:"type"`
+	// Units added for restock, removed for waste, signed for adjust
+	Quantity int    `json:"quantity"`
+	Reason   string `json:"reason"`
+}
+
+// Ledger and stored stock of one product or variant
+type inventoryBalance struct {
+	ProductID   string  `json:"product_id"`
+	VariantID   *string `json:"variant_id,omitempty"`
+	ProductName string  `json:"product_name"`
+	VariantName string  `json:"variant_name,omitempty"`
+	Stock       int     `json:"stock"`
+	LedgerStock int     `json:"ledger_stock"`
+	InSync      bool    `json:"in_sync"`
+}
+
+// Change stock and record the movement in one transaction, then react to
+// the new level. Returns nil if the product or variant does not exist.
+func recordStockMovement(m models.InventoryMovement) (*stockChange, error) {
+	if !db.Ready() {
+		return nil, gorm.ErrInvalidDB
+	}
+
+	var change *stockChange
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		change, err = adjustStock(tx, m.ProductID, m.VariantID, m.Quantity)
+		if err != nil || change == nil {
+			return err
+		}
+		m.Quantity = change.After - change.Before
+		m.StockBefore = change.Before
+		m.StockAfter = change.After
+		if m.Actor == "" {
+			m.Actor = "system"
+		}
+		return tx.Create(&m).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if change != nil {
+		reactToStockChange(*change)
+	}
+	return change, nil
+}
+
+// Record stock set outside adjustStock (new products and variants) so the
+// ledger starts from it
+func recordOpeningStock(productID string, variantID *string, stock int, actor string) {
+	if stock == 0 {
+		return
+	}
+	err := db.DB.Create(&models.InventoryMovement{
+		ProductID:   productID,
+		VariantID:   variantID,
+		Type:        MovementRestock,
+		Quantity:    stock,
+		StockBefore: 0,
+		StockAfter:  stock,
+		Reason:      "Initial stock",
+		Actor:       actor,
+	}).Error
+	if err != nil {
+		log.Printf("❌ Error recording initial stock for product %s: %v", productID, err)
+	}
+}
+
+// Stock given to a new variant when the form leaves it at zero
+const defaultVariantStock = 100
+
+// Changes that bring the stored variants of a product in line with the form
+type variantSync struct {
+	Create []models.ProductVariant
+	Update []models.ProductVariant
+	Delete []models.ProductVariant
+}
+
+// Match the form variants to the stored ones, by id and then by name. New
+// variants get the default stock; stored ones keep their stock and
+// auto_disabled state, which belong to the ledger and the sold-out handling,
+// so editing a product never restocks a sold out variant.
+func planVariantSync(productID string, existing, requested []models.ProductVariant) variantSync {
+	byID := make(map[string]models.ProductVariant)
+	byName := make(map[string]models.ProductVariant)
+	for _, v := range existing {
+		byID[v.ID] = v
+		byName[v.Name] = v
+	}
+
+	var plan variantSync
+	keptNames := make(map[string]bool)
+	keptIDs := make(map[string]bool)
+	for _, v := range requested {
+		name := strings.TrimSpace(v.Name)
+		if name == "" || keptNames[name] {
+			continue
+		}
+		keptNames[name] = true
+
+		old, ok := byID[v.ID]
+		if !ok || keptIDs[old.ID] {
+			old, ok = byName[name]
+		}
+		if !ok || keptIDs[old.ID] {
+			stock := v.Stock
+			if stock == 0 {
+				stock = defaultVariantStock
+			}
+			plan.Create = append(plan.Create, models.ProductVariant{
+				ProductID:   productID,
+				Name:        name,
+				Price:       v.Price,
+				Stock:       stock,
+				IsAvailable: v.IsAvailable,
+			})
+			continue
+		}
+
+		keptIDs[old.ID] = true
+		old.Name = name
+		old.Price = v.Price
+		// Sold out variants stay hidden until they are restocked
+		if !old.AutoDisabled {
+			old.IsAvailable = v.IsAvailable
+		}
+		plan.Update = append(plan.Update, old)
+	}
+
+	for _, v := range existing {
+		if !keptIDs[v.ID] {
+			plan.Delete = append(plan.Delete, v)
+		}
+	}
+	return plan
+}
+
+// Update the variants of a product to match the form, see planVariantSync.
+// Variant stock only changes through the inventory adjust endpoint.
+func syncProductVariants(product models.Product, variants []models.ProductVariant, actor string) {
+	var existing []models.ProductVariant
+	db.DB.Where("product_id = ?", product.ID).Find(&existing)
+	plan := planVariantSync(product.ID, existing, variants)
+
+	for _, variant := range plan.Create {
+		if err := db.DB.Create(&variant).Error; err != nil {
+			log.Printf("❌ Error creating variant %s: %v", variant.Name, err)
+			continue
+		}
+		if !variant.IsAvailable {
+			db.DB.Model(&variant).Update("is_available", false)
+		}
+		recordOpeningStock(product.ID, &variant.ID, variant.Stock, actor)
+		log.Printf("  - Variant created: %s, Price: %.0f, Available: %v", variant.Name, variant.Price, variant.IsAvailable)
+	}
+
+	for _, variant := range plan.Update {
+		db.DB.Model(&variant).Updates(map[string]interface{}{
+			"name":         variant.Name,
+			"price":        variant.Price,
+			"is_available": variant.IsAvailable,
+			"updated_at":   time.Now(),
+		})
+		log.Printf("  - Variant updated: %s, Price: %.0f, Available: %v", variant.Name, variant.Price, variant.IsAvailable)
+	}
+
+	for _, variant := range plan.Delete {
+		db.DB.Delete(&variant)
+		log.Printf("  - Variant deleted: %s", variant.Name)
+	}
+}
+
+// Products hook: start the ledger from the stock of a new product and its variants
+func productCreated(c *fiber.Ctx, product models.Product, variants []models.ProductVariant) {
+	actor := requestActor(c)
+	recordOpeningStock(product.ID, nil, product.Stock, actor)
+	for _, v := range variants {
+		if v.ID != "" {
+			variantID := v.ID
+			recordOpeningStock(product.ID, &variantID, v.Stock, actor)
+		}
+	}
+}
+
+// Products hook: stock edited on the product form goes through the ledger
+func productUpdated(c *fiber.Ctx, product models.Product, oldStock int, variants []models.ProductVariant) {
+	actor := requestActor(c)
+	if product.Stock != oldStock {
+		_, err := recordStockMovement(models.InventoryMovement{
+			ProductID: product.ID,
+			Type:      MovementAdjust,
+			Quantity:  product.Stock - oldStock,
+			Reason:    "Updated from product form",
+			Actor:     actor,
+		})
+		if err != nil {
+			log.Printf("❌ Error recording stock change for product %s: %v", product.ID, err)
+		}
+	}
+
+	// Update variants in place, new ones are created and missing ones deleted
+	syncProductVariants(product, variants, actor)
+}
+
+// Who made the request, the email of the admin session the route requires
+func requestActor(c *fiber.Ctx) string {
+	return auth.Current(c).Email
+}
+
+// Stored stock next to the ledger total for products (and their variants),
+// all products when productID is empty
+func inventoryBalances(productID string) ([]inventoryBalance, error) {
+	query := `
+		SELECT p.id AS product_id, NULL::uuid AS variant_id, p.name AS product_name, '' AS variant_name,
+			p.stock, COALESCE((
+				SELECT SUM(m.quantity) FROM inventory_movements m
+				WHERE m.product_id = p.id AND m.variant_id IS NULL
+			), 0) AS ledger_stock
+		FROM products p
+		WHERE (@product = '' OR p.id::text = @product)
+		UNION ALL
+		SELECT v.product_id, v.id, p.name, v.name,
+			v.stock, COALESCE((
+				SELECT SUM(m.quantity) FROM inventory_movements m
+				WHERE m.variant_id = v.id
+			), 0)
+		FROM product_variants v
+		JOIN products p ON p.id = v.product_id
+		WHERE (@product = '' OR v.product_id::text = @product)
+		ORDER BY product_name, variant_name
+	`
+	var balances []inventoryBalance
+	if err := db.DB.Raw(query, map[string]interface{}{"product": productID}).Scan(&balances).Error; err != nil {
+		return nil, err
+	}
+	for i := range balances {
+		balances[i].InSync = balances[i].Stock == balances[i].LedgerStock
+	}
+	return balances, nil
+}
+
+func registerInventoryRoutes(app *fiber.App) {
+	// Admin: Restock, write off or correct stock
+	app.Post("/api/admin/inventory/adjust", func(c *fiber.Ctx) error {
+		var req InventoryAdjustRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+		req.Reason = strings.TrimSpace(req.Reason)
+		if req.VariantID != nil && *req.VariantID == "" {
+			req.VariantID = nil
+		}
+
+		delta := req.Quantity
+		switch req.Type {
+		case MovementRestock:
+			if req.Quantity <= 0 {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": "Quantity must be positive",
+				})
+			}
+		case MovementWaste:
+			if req.Quantity <= 0 {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": "Quantity must be positive",
+				})
+			}
+			delta = -req.Quantity
+		case MovementAdjust:
+			if req.Quantity == 0 {
+				return c.Status(400).JSON(fiber.Map{
+					"success": false,
+					"message": "Quantity must not be zero",
+				})
+			}
+		default:
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Type must be restock, waste or adjust",
+			})
+		}
+		if req.Type != MovementRestock && req.Reason == "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Reason is required",
+			})
+		}
+
+		// Check the current level so removals never silently stop at zero
+		var stocks []int
+		if req.VariantID != nil {
+			db.DB.Model(&models.ProductVariant{}).Where("id = ? AND product_id = ?", *req.VariantID, req.ProductID).Pluck("stock", &stocks)
+		} else {
+			db.DB.Model(&models.Product{}).Where("id = ?", req.ProductID).Pluck("stock", &stocks)
+		}
+		if len(stocks) == 0 {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Product not found",
+			})
+		}
+		if stocks[0]+delta < 0 {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Not enough stock",
+			})
+		}
+
+		change, err := recordStockMovement(models.InventoryMovement{
+			ProductID: req.ProductID,
+			VariantID: req.VariantID,
+			Type:      req.Type,
+			Quantity:  delta,
+			Reason:    req.Reason,
+			Actor:     requestActor(c),
+		})
+		if err != nil {
+			log.Printf("Error adjusting stock: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to adjust stock",
+			})
+		}
+		if change == nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Product not found",
+			})
+		}
+
+		log.Printf("📦 Stock %s for %s: %d → %d (%s)", req.Type, stockLabel(*change), change.Before, change.After, requestActor(c))
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data": fiber.Map{
+				"product_id":   change.ProductID,
+				"variant_id":   req.VariantID,
+				"stock_before": change.Before,
+				"stock_after":  change.After,
+			},
+			"message": "Stock updated",
+		})
+	})
+
+	// Admin: Stock movement history of a product (?variant_id=&limit=100)
+	app.Get("/api/admin/inventory/products/:id/movements", func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		var product models.Product
+		if err := db.DB.First(&product, "id = ?", id).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Product not found",
+			})
+		}
+
+		limit := c.QueryInt("limit", 100)
+		if limit < 1 || limit > 1000 {
+			limit = 100
+		}
+
+		query := db.DB.Where("product_id = ?", id).Order("created_at DESC").Limit(limit)
+		if variantID := c.Query("variant_id"); variantID != "" {
+			query = query.Where("variant_id = ?", variantID)
+		}
+
+		var movements []models.InventoryMovement
+		if err := query.Find(&movements).Error; err != nil {
+			log.Printf("Error fetching inventory movements: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to fetch movements",
+			})
+		}
+
+		balances, err := inventoryBalances(id)
+		if err != nil {
+			log.Printf("Error fetching inventory balances: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to fetch movements",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data": fiber.Map{
+				"product":   product,
+				"balances":  balances,
+				"movements": movements,
+			},
+		})
+	})
+
+	// Admin: Products whose stored stock does not match the ledger
+	app.Get("/api/admin/inventory/check", func(c *fiber.Ctx) error {
+		balances, err := inventoryBalances("")
+		if err != nil {
+			log.Printf("Error checking inventory: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to check inventory",
+			})
+		}
+
+		mismatched := []inventoryBalance{}
+		for _, b := range balances {
+			if !b.InSync {
+				mismatched = append(mismatched, b)
+			}
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    mismatched,
+			"checked": len(balances),
+		})
+	})
+}
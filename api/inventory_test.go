@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"scaff-food-backend/internal/models"
+)
+
+func TestPlanVariantSyncKeepsStoredStock(t *testing.T) {
+	existing := []models.ProductVariant{
+		{ID: "v1", ProductID: "p1", Name: "3pcs", Price: 10000, Stock: 0, IsAvailable: false, AutoDisabled: true},
+		{ID: "v2", ProductID: "p1", Name: "5pcs", Price: 15000, Stock: 7, IsAvailable: true},
+		{ID: "v3", ProductID: "p1", Name: "10pcs", Price: 28000, Stock: 3, IsAvailable: true},
+	}
+	// The form sends stock 0 for everything and marks the sold out variant available
+	requested := []models.ProductVariant{
+		{ID: "v1", Name: "3pcs", Price: 11000, IsAvailable: true},
+		{ID: "v2", Name: "5 pcs", Price: 16000, IsAvailable: false},
+		{Name: "Jumbo", Price: 40000, IsAvailable: true},
+	}
+
+	plan := planVariantSync("p1", existing, requested)
+
+	if len(plan.Update) != 2 {
+		t.Fatalf("updates = %+v, want v1 and v2", plan.Update)
+	}
+	soldOut, renamed := plan.Update[0], plan.Update[1]
+	if soldOut.ID != "v1" || soldOut.Stock != 0 || !soldOut.AutoDisabled || soldOut.IsAvailable {
+		t.Errorf("sold out variant = %+v, want stock 0, still auto disabled and hidden", soldOut)
+	}
+	if soldOut.Price != 11000 {
+		t.Errorf("sold out variant price = %.0f, want 11000", soldOut.Price)
+	}
+	if renamed.ID != "v2" || renamed.Name != "5 pcs" || renamed.Stock != 7 || renamed.IsAvailable {
+		t.Errorf("renamed variant = %+v, want v2 renamed with stock 7 and hidden", renamed)
+	}
+
+	if len(plan.Create) != 1 || plan.Create[0].Name != "Jumbo" || plan.Create[0].Stock != defaultVariantStock || plan.Create[0].ProductID != "p1" {
+		t.Errorf("creates = %+v, want Jumbo with the default stock", plan.Create)
+	}
+	if len(plan.Delete) != 1 || plan.Delete[0].ID != "v3" {
+		t.Errorf("deletes = %+v, want v3", plan.Delete)
+	}
+}
+
+func TestPlanVariantSyncMatchesByName(t *testing.T) {
+	existing := []models.ProductVariant{{ID: "v1", Name: "3pcs", Stock: 0, AutoDisabled: true}}
+
+	// Forms that do not send ids still update the stored variant
+	plan := planVariantSync("p1", existing, []models.ProductVariant{{Name: " 3pcs ", Price: 9000}})
+
+	if len(plan.Create) != 0 || len(plan.Delete) != 0 {
+		t.Fatalf("plan = %+v, want only an update", plan)
+	}
+	if len(plan.Update) != 1 || plan.Update[0].ID != "v1" || plan.Update[0].Stock != 0 {
+		t.Errorf("updates = %+v, want v1 with stock 0", plan.Update)
+	}
+}
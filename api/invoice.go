@@ -0,0 +1,270 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/orders"
+	"scaff-food-backend/internal/pdf"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ==================== INVOICES ====================
+
+// Brand lime of the SCAFF*FOOD emails (#bff000)
+const (
+	brandRed   = 0xbf / 255.0
+	brandGreen = 0xf0 / 255.0
+	brandBlue  = 0x00 / 255.0
+)
+
+// Give the order its invoice number if it has none yet. Numbers come from
+// a sequence, so they are sequential and never reused.
+func assignInvoiceNumber(order *models.Order) error {
+	if order.InvoiceNumber != "" {
+		return nil
+	}
+	err := db.DB.Exec(`
+		UPDATE orders
+		SET invoice_number = 'INV-' || to_char(NOW(), 'YYYYMM') || '-' || lpad(nextval('invoice_number_seq')::text, 5, '0'),
+			invoiced_at = NOW()
+		WHERE id = ? AND invoice_number IS NULL
+	`, order.ID).Error
+	if err != nil {
+		return err
+	}
+	// Another request may have numbered it first, read back what was stored
+	return db.DB.Select("invoice_number", "invoiced_at").First(order, "id = ?", order.ID).Error
+}
+
+// QRIS codes the order was paid with, from the products ordered
+func orderQRISNames(orderID string) []string {
+	var names []string
+	db.DB.Table("order_items oi").
+		Joins("JOIN products p ON p.id = oi.product_id").
+		Joins("JOIN qris_codes q ON q.id = p.qris_id").
+		Where("oi.order_id = ?", orderID).
+		Distinct().
+		Order("q.name").
+		Pluck("q.name", &names)
+	return names
+}
+
+// Options of an order item not already in its name, for invoices and tickets
+func orderItemOptions(item models.OrderItem) []string {
+	var options []string
+	if item.VariantName != "" && !strings.Contains(item.ProductName, item.VariantName) {
+		options = append(options, item.VariantName)
+	}
+	if item.ConditionName != "" && !strings.Contains(item.ProductName, item.ConditionName) {
+		options = append(options, item.ConditionName)
+	}
+	if len(item.Addons) > 0 {
+		options = append(options, "Addon: "+strings.Join(item.Addons, ", "))
+	}
+	return options
+}
+
+// Invoice of an order as an A4 PDF, a receipt once it is paid
+func orderInvoicePDF(order models.Order, items []models.OrderItem, qrisNames []string) []byte {
+	const (
+		margin   = 40.0
+		right    = 555.0
+		colQty   = 360.0
+		colPrice = 460.0
+		rowPad   = 6.0
+		fontSize = 10.0
+	)
+
+	paid := order.PaymentStatus == "paid"
+	title := "INVOICE"
+	if paid {
+		title = "KUITANSI"
+	}
+
+	doc := pdf.New()
+	page := doc.AddPage()
+
+	// Header band like the email layout
+	page.FillRectRGB(margin, 40, right-margin, 60, brandRed, brandGreen, brandBlue)
+	page.StrokeRect(margin, 40, right-margin, 60, 2)
+	page.Text(margin+16, 80, 26, true, "SCAFF*FOOD")
+	page.TextRight(right-16, 68, 16, true, title)
+	page.TextRight(right-16, 86, 10, false, order.InvoiceNumber)
+
+	// Invoice and customer details
+	y := 130.0
+	details := [][2]string{
+		{"No. Invoice", order.InvoiceNumber},
+		{"No. Pesanan", order.OrderNumber},
+		{"Tanggal Pesan", order.CreatedAt.Format("02-01-2006 15:04")},
+	}
+	if order.InvoicedAt != nil {
+		details = append(details, [2]string{"Tanggal Invoice", order.InvoicedAt.Format("02-01-2006")})
+	}
+	if order.DeliveryDate != nil {
+		details = append(details, [2]string{"Tanggal Kirim", order.DeliveryDate.Format("02-01-2006")})
+	}
+	for i, d := range details {
+		page.Text(margin, y+float64(i)*14, fontSize, true, d[0])
+		page.Text(margin+90, y+float64(i)*14, fontSize, false, ": "+d[1])
+	}
+
+	customerX := 320.0
+	page.Text(customerX, y, fontSize, true, "Kepada")
+	cy := y + 14
+	for _, line := range []string{order.CustomerName, order.CustomerPhone, order.CustomerEmail} {
+		if line != "" {
+			page.Text(customerX, cy, fontSize, false, pdf.Fit(line, fontSize, false, right-customerX))
+			cy += 14
+		}
+	}
+	address := strings.TrimSpace(order.DeliveryAddress)
+	if order.DeliveryLocation != "" {
+		address = strings.TrimSpace(address + " (" + order.DeliveryLocation + ")")
+	}
+	for _, line := range pdf.Wrap(address, fontSize, false, right-customerX) {
+		if line != "" {
+			page.Text(customerX, cy, fontSize, false, line)
+			cy += 14
+		}
+	}
+
+	y += float64(len(details)) * 14
+	if cy > y {
+		y = cy
+	}
+	y += 16
+
+	// Items
+	header := func() {
+		page.FillRect(margin, y, right-margin, 20, 0.9)
+		page.Text(margin+6, y+14, 9, true, "Item")
+		page.TextRight(colQty, y+14, 9, true, "Qty")
+		page.TextRight(colPrice, y+14, 9, true, "Harga")
+		page.TextRight(right-6, y+14, 9, true, "Subtotal")
+		y += 20
+	}
+	header()
+	for _, item := range items {
+		details := strings.Join(orderItemOptions(item), "  |  ")
+		height := 18.0
+		if details != "" {
+			height += 12
+		}
+		if y+height > doc.Height-180 {
+			page = doc.AddPage()
+			y = 50
+			header()
+		}
+		page.Text(margin+6, y+13, fontSize, false, pdf.Fit(item.ProductName, fontSize, false, colQty-margin-40))
+		if details != "" {
+			page.Text(margin+14, y+25, 8, false, pdf.Fit(details, 8, false, colQty-margin-48))
+		}
+		page.TextRight(colQty, y+13, fontSize, false, strconv.Itoa(item.Quantity))
+		page.TextRight(colPrice, y+13, fontSize, false, formatRupiah(item.ProductPrice))
+		page.TextRight(right-6, y+13, fontSize, false, formatRupiah(item.Subtotal))
+		y += height + rowPad
+		page.Line(margin, y-rowPad/2, right, y-rowPad/2, 0.3)
+	}
+
+	// Totals
+	y += 10
+	totals := [][2]string{
+		{"Subtotal", formatRupiah(order.Subtotal)},
+		{"Ongkos Kirim", formatRupiah(order.DeliveryFee)},
+	}
+	for _, t := range totals {
+		page.Text(colQty, y, fontSize, false, t[0])
+		page.TextRight(right-6, y, fontSize, false, t[1])
+		y += 16
+	}
+	page.Line(colQty, y-8, right, y-8, 0.7)
+	page.Text(colQty, y+6, 12, true, "Total")
+	page.TextRight(right-6, y+6, 12, true, formatRupiah(order.Total))
+	y += 34
+
+	// Payment
+	page.Text(margin, y, fontSize, true, "Pembayaran")
+	y += 14
+	page.Text(margin, y, fontSize, false, "Metode: "+order.PaymentMethod)
+	y += 14
+	if strings.Contains(strings.ToLower(order.PaymentMethod), "qris") && len(qrisNames) > 0 {
+		page.Text(margin, y, fontSize, false, "QRIS: "+strings.Join(qrisNames, ", "))
+		y += 14
+	}
+
+	status := "BELUM LUNAS"
+	if paid {
+		status = "LUNAS"
+	}
+	stampW := pdf.TextWidth(status, 14, true) + 24
+	if paid {
+		page.FillRectRGB(right-stampW, y-28, stampW, 26, brandRed, brandGreen, brandBlue)
+	}
+	page.StrokeRect(right-stampW, y-28, stampW, 26, 2)
+	page.TextCenter(right-stampW/2, y-10, 14, true, status)
+
+	// Footer
+	page.Line(margin, doc.Height-70, right, doc.Height-70, 0.5)
+	page.TextCenter(doc.Width/2, doc.Height-54, 9, false, "Terima kasih telah memesan di SCAFF*FOOD!")
+	page.TextCenter(doc.Width/2, doc.Height-42, 8, false, fmt.Sprintf("Dokumen ini dibuat otomatis pada %s dan sah tanpa tanda tangan.", time.Now().Format("02-01-2006 15:04")))
+
+	return doc.Bytes()
+}
+
+func registerInvoiceRoutes(app *fiber.App) {
+	// Invoice or receipt of an order as PDF, numbered the first time it is
+	// printed. Same access as the order itself, ?token= for tracking links.
+	app.Get("/api/orders/:id/invoice.pdf", auth.OptionalSession, func(c *fiber.Ctx) error {
+		id := c.Params("id")
+		if len(id) != 36 {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid order ID format",
+			})
+		}
+
+		var order models.Order
+		if err := db.DB.First(&order, "id = ?", id).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Order not found",
+			})
+		}
+		if !orders.CanView(auth.Current(c), order, c.Query("token")) {
+			return c.Status(403).JSON(fiber.Map{
+				"success": false,
+				"message": "Tidak diizinkan melihat pesanan ini",
+			})
+		}
+		if models.IsCancelledStatus(order.OrderStatus) {
+			return c.Status(409).JSON(fiber.Map{
+				"success": false,
+				"message": "Pesanan yang dibatalkan tidak memiliki invoice.",
+			})
+		}
+
+		if err := assignInvoiceNumber(&order); err != nil {
+			log.Printf("Error assigning invoice number to %s: %v", order.OrderNumber, err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to create invoice",
+			})
+		}
+
+		var items []models.OrderItem
+		db.DB.Where("order_id = ?", order.ID).Order("created_at ASC").Find(&items)
+
+		c.Set(fiber.HeaderContentType, "application/pdf")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s.pdf"`, order.InvoiceNumber))
+		return c.Send(orderInvoicePDF(order, items, orderQRISNames(order.ID)))
+	})
+}
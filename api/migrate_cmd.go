@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/migrate"
+	"scaff-food-backend/migrations"
+)
+
+// ==================== MIGRATIONS ====================
+
+const migrateUsage = `usage: main migrate <command>
+
+commands:
+  status                  list migrations and whether they are applied
+  up                      apply every pending migration
+  down [steps]            roll back the last steps migrations (default 1),
+                          down to 015 at most
+  create <description>    write an empty migration to MIGRATIONS_DIR
+  baseline <number>       record migrations up to number as applied without
+                          running them, for existing databases. Use the last
+                          migration the schema already has, e.g. 015 for a
+                          database set up by the old docker-compose initdb`
+
+func newMigrator() (*migrate.Migrator, error) {
+	if db.DB == nil {
+		return nil, fmt.Errorf("database not connected")
+	}
+	sqlDB, err := db.DB.DB()
+	if err != nil {
+		return nil, err
+	}
+	return migrate.New(sqlDB, migrations.FS)
+}
+
+// Apply pending migrations before serving. A database set up by hand before
+// the runner existed stops startup until it is baselined, the code expects
+// the newer schema.
+func migrateOnBoot() {
+	migrator, err := newMigrator()
+	if err != nil {
+		log.Fatalf("❌ Failed to load migrations: %v", err)
+	}
+
+	count, err := migrator.Up(context.Background())
+	if errors.Is(err, migrate.ErrUnversioned) {
+		log.Fatalf("❌ %v (015 for a database set up by the old docker-compose initdb), then restart", err)
+	}
+	if err != nil {
+		log.Fatalf("❌ Migration failed: %v", err)
+	}
+
+	if count > 0 {
+		log.Printf("✅ Applied %d migration(s)", count)
+	} else {
+		log.Println("✅ Database schema is up to date")
+	}
+}
+
+// runMigrateCommand handles `main migrate ...` and exits
+func runMigrateCommand(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+
+	// create only touches the migrations directory
+	if args[0] == "create" {
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			os.Exit(2)
+		}
+		filename, err := migrate.Create(appConfig.MigrationsDir, args[1])
+		if err != nil {
+			log.Fatalf("❌ Failed to create migration: %v", err)
+		}
+		fmt.Println(filename)
+		return
+	}
+
+	db.Connect(appConfig.Database)
+	if !db.Ready() {
+		log.Fatal("❌ Database not connected")
+	}
+	migrator, err := newMigrator()
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatalf("❌ Failed to read migration status: %v", err)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tSTATUS\tAPPLIED AT")
+		for _, status := range statuses {
+			state, appliedAt := "pending", ""
+			if status.Applied {
+				state = "applied"
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if status.Modified {
+				state = "modified"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", status.Version, state, appliedAt)
+		}
+		w.Flush()
+
+	case "up":
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatalf("❌ Migration failed: %v", err)
+		}
+		log.Printf("✅ Applied %d migration(s)", count)
+
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				log.Fatalf("❌ Invalid number of steps: %s", args[1])
+			}
+		}
+		count, err := migrator.Down(ctx, steps)
+		if err != nil {
+			log.Fatalf("❌ Rollback failed after %d migration(s): %v", count, err)
+		}
+		log.Printf("✅ Rolled back %d migration(s)", count)
+
+	case "baseline":
+		// No default: baselining migrations the schema does not have yet
+		// would skip them for good
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			os.Exit(2)
+		}
+		number, err := strconv.Atoi(args[1])
+		if err != nil || number < 1 || number > migrator.Latest() {
+			log.Fatalf("❌ Invalid migration number: %s (1 to %03d)", args[1], migrator.Latest())
+		}
+		count, err := migrator.Baseline(ctx, number)
+		if err != nil {
+			log.Fatalf("❌ Baseline failed: %v", err)
+		}
+		log.Printf("✅ Recorded %d migration(s) up to %03d as applied", count, number)
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		os.Exit(2)
+	}
+}
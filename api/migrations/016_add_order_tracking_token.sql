@@ -0,0 +1,14 @@
+-- Add tracking_token column to orders table
+-- Random secret used by the public /api/track/:token endpoint
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS tracking_token VARCHAR(64);
+
+-- Give existing orders a token
+UPDATE orders SET tracking_token = encode(gen_random_bytes(32), 'hex') WHERE tracking_token IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_tracking_token ON orders(tracking_token);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_orders_tracking_token;
+ALTER TABLE orders DROP COLUMN IF EXISTS tracking_token;
@@ -0,0 +1,27 @@
+-- Create customer_addresses table for saved delivery addresses
+CREATE TABLE IF NOT EXISTS customer_addresses (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    label VARCHAR(50),
+    recipient_name VARCHAR(100),
+    recipient_phone VARCHAR(20),
+    address TEXT NOT NULL,
+    delivery_location VARCHAR(50) DEFAULT 'TB',
+    is_default BOOLEAN DEFAULT false,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_customer_addresses_user_id ON customer_addresses(user_id);
+
+-- Orders link to the customer account, guest orders keep user_id NULL
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_orders_user_id ON orders(user_id);
+
+COMMENT ON TABLE customer_addresses IS 'Saved delivery addresses for registered customers';
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_orders_user_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS user_id;
+
+DROP TABLE IF EXISTS customer_addresses;
@@ -0,0 +1,59 @@
+-- Normalize customer phone numbers to E.164 (+62...) and emails to lower case
+-- Matches validate.NormalizePhone/NormalizeEmail (internal/validate), rows that
+-- don't look like an Indonesian number are left untouched
+
+-- Helper: strip separators and rewrite +62 / 62 / 0 prefixes to +62
+CREATE OR REPLACE FUNCTION normalize_id_phone(raw TEXT)
+RETURNS TEXT AS $$
+DECLARE
+    cleaned TEXT;
+BEGIN
+    IF raw IS NULL THEN
+        RETURN NULL;
+    END IF;
+
+    cleaned := regexp_replace(trim(raw), '[\s\-\.\(\)]', '', 'g');
+
+    IF cleaned !~ '^(\+62|62|0)[0-9]{9,12}$' THEN
+        RETURN raw;
+    END IF;
+
+    cleaned := regexp_replace(cleaned, '^(\+62|62|0)', '');
+    cleaned := regexp_replace(cleaned, '^0', '');
+    RETURN '+62' || cleaned;
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Orders
+UPDATE orders
+SET customer_phone = normalize_id_phone(customer_phone)
+WHERE customer_phone IS DISTINCT FROM normalize_id_phone(customer_phone);
+
+UPDATE orders
+SET customer_email = LOWER(TRIM(customer_email))
+WHERE customer_email <> LOWER(TRIM(customer_email));
+
+-- Users
+UPDATE users
+SET phone = normalize_id_phone(phone)
+WHERE phone IS DISTINCT FROM normalize_id_phone(phone);
+
+-- users.email is unique, skip rows whose normalized email is already taken
+UPDATE users
+SET email = LOWER(TRIM(email))
+WHERE email <> LOWER(TRIM(email))
+  AND NOT EXISTS (
+      SELECT 1 FROM users other
+      WHERE other.email = LOWER(TRIM(users.email)) AND other.id <> users.id
+  );
+
+-- Lookups by customer contact
+CREATE INDEX IF NOT EXISTS idx_orders_customer_phone ON orders(customer_phone);
+CREATE INDEX IF NOT EXISTS idx_orders_customer_email ON orders(customer_email);
+
+DROP FUNCTION normalize_id_phone(TEXT);
+
+-- +migrate Down
+-- Normalized phone numbers and emails are kept, the originals are gone
+DROP INDEX IF EXISTS idx_orders_customer_email;
+DROP INDEX IF EXISTS idx_orders_customer_phone;
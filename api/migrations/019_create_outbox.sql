@@ -0,0 +1,25 @@
+-- Create outbox_messages table for asynchronous email / WhatsApp delivery
+CREATE TABLE IF NOT EXISTS outbox_messages (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    channel VARCHAR(50) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 5,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    locked_at TIMESTAMP,
+    sent_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Workers poll pending messages that are due
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_due ON outbox_messages(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_created_at ON outbox_messages(created_at DESC);
+
+COMMENT ON TABLE outbox_messages IS 'Outgoing emails and notifications, status: pending, processing, sent, dead';
+
+-- +migrate Down
+DROP TABLE IF EXISTS outbox_messages;
@@ -0,0 +1,30 @@
+-- Create order_events table, the log behind the admin realtime order stream
+CREATE TABLE IF NOT EXISTS order_events (
+    id BIGSERIAL PRIMARY KEY,
+    type VARCHAR(50) NOT NULL,
+    order_id UUID,
+    data JSONB NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_events_order_id ON order_events(order_id);
+CREATE INDEX IF NOT EXISTS idx_order_events_created_at ON order_events(created_at);
+
+-- Notify listeners (every API replica) of each new event id
+CREATE OR REPLACE FUNCTION notify_order_event() RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('order_events', NEW.id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS order_events_notify ON order_events;
+CREATE TRIGGER order_events_notify
+    AFTER INSERT ON order_events
+    FOR EACH ROW EXECUTE FUNCTION notify_order_event();
+
+COMMENT ON TABLE order_events IS 'Order events for the realtime stream, types: order.created, order.status_changed, payment.updated';
+
+-- +migrate Down
+DROP TABLE IF EXISTS order_events;
+DROP FUNCTION IF EXISTS notify_order_event();
@@ -0,0 +1,23 @@
+-- Create webhooks table for outgoing integrations (spreadsheets, Discord, ...)
+CREATE TABLE IF NOT EXISTS webhooks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL,
+    events TEXT[] NOT NULL DEFAULT ARRAY[]::TEXT[],
+    format VARCHAR(20) NOT NULL DEFAULT 'json',
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_webhooks_events ON webhooks USING GIN(events);
+
+-- Deliveries are outbox messages on the webhook channel, recipient is the webhook id
+CREATE INDEX IF NOT EXISTS idx_outbox_messages_recipient ON outbox_messages(channel, recipient, created_at DESC);
+
+COMMENT ON TABLE webhooks IS 'Outgoing webhooks, events: order.created, order.status_changed, payment.updated, product.stock_low';
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_outbox_messages_recipient;
+DROP TABLE IF EXISTS webhooks;
@@ -0,0 +1,19 @@
+-- Low-stock threshold per product, alerts fire when stock drops to it
+ALTER TABLE products ADD COLUMN IF NOT EXISTS low_stock_threshold INTEGER NOT NULL DEFAULT 5;
+
+-- Set when the product was hidden because it sold out, restocking brings it back
+ALTER TABLE products ADD COLUMN IF NOT EXISTS auto_disabled BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE product_variants ADD COLUMN IF NOT EXISTS auto_disabled BOOLEAN NOT NULL DEFAULT false;
+
+-- Variant ordered, variant stock is deducted instead of product stock when set.
+-- The foreign key to product_variants is added by 030.
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_id UUID;
+
+COMMENT ON COLUMN products.low_stock_threshold IS 'Stock level at or below which admins are alerted';
+COMMENT ON COLUMN products.auto_disabled IS 'Hidden automatically at zero stock, re-enabled on restock';
+
+-- +migrate Down
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+ALTER TABLE product_variants DROP COLUMN IF EXISTS auto_disabled;
+ALTER TABLE products DROP COLUMN IF EXISTS auto_disabled;
+ALTER TABLE products DROP COLUMN IF EXISTS low_stock_threshold;
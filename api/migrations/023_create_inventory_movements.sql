@@ -0,0 +1,36 @@
+-- Create inventory_movements ledger, every stock change of a product or variant
+CREATE TABLE IF NOT EXISTS inventory_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id UUID,
+    type VARCHAR(20) NOT NULL,
+    quantity INTEGER NOT NULL,
+    stock_before INTEGER NOT NULL,
+    stock_after INTEGER NOT NULL,
+    reason TEXT,
+    actor VARCHAR(255) NOT NULL DEFAULT 'system',
+    order_id UUID REFERENCES orders(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_product ON inventory_movements(product_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_variant ON inventory_movements(variant_id) WHERE variant_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_order ON inventory_movements(order_id) WHERE order_id IS NOT NULL;
+
+-- Opening balance so the ledger total matches the current stock
+INSERT INTO inventory_movements (product_id, type, quantity, stock_before, stock_after, reason, actor)
+SELECT id, 'adjust', stock, 0, stock, 'Opening balance', 'system'
+FROM products
+WHERE stock <> 0
+  AND NOT EXISTS (SELECT 1 FROM inventory_movements m WHERE m.product_id = products.id AND m.variant_id IS NULL);
+
+INSERT INTO inventory_movements (product_id, variant_id, type, quantity, stock_before, stock_after, reason, actor)
+SELECT product_id, id, 'adjust', stock, 0, stock, 'Opening balance', 'system'
+FROM product_variants
+WHERE stock <> 0
+  AND NOT EXISTS (SELECT 1 FROM inventory_movements m WHERE m.variant_id = product_variants.id);
+
+COMMENT ON TABLE inventory_movements IS 'Stock ledger, types: restock, sale, cancel_return, waste, adjust';
+
+-- +migrate Down
+DROP TABLE IF EXISTS inventory_movements;
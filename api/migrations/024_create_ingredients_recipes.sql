@@ -0,0 +1,40 @@
+-- Create ingredients table, raw materials with their cost per unit
+CREATE TABLE IF NOT EXISTS ingredients (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL UNIQUE,
+    unit VARCHAR(20) NOT NULL,
+    cost_per_unit DECIMAL(12, 4) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create recipes table, ingredient quantity per unit of a product or variant
+-- (variant_id NULL is the product recipe, used by variants without their own)
+CREATE TABLE IF NOT EXISTS recipes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    variant_id UUID REFERENCES product_variants(id) ON DELETE CASCADE,
+    ingredient_id UUID NOT NULL REFERENCES ingredients(id) ON DELETE RESTRICT,
+    quantity DECIMAL(12, 4) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_recipes_product ON recipes(product_id, variant_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recipes_unique_ingredient
+    ON recipes(product_id, COALESCE(variant_id, '00000000-0000-0000-0000-000000000000'::uuid), ingredient_id);
+
+-- Cost of goods sold, computed once when the order is completed
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS cogs DECIMAL(12, 2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cogs DECIMAL(12, 2) NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS cogs_computed_at TIMESTAMP;
+
+COMMENT ON COLUMN orders.cogs IS 'Cost of goods sold from recipes and ingredient costs at completion';
+
+-- +migrate Down
+ALTER TABLE orders DROP COLUMN IF EXISTS cogs_computed_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS cogs;
+ALTER TABLE order_items DROP COLUMN IF EXISTS cogs;
+
+DROP TABLE IF EXISTS recipes;
+DROP TABLE IF EXISTS ingredients;
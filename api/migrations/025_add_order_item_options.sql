@@ -0,0 +1,10 @@
+-- Options chosen for each order item, used by the kitchen production plan.
+-- Older items only have them inside product_name, e.g. "Cookies (Coklat) - Pedas"
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS variant_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS condition_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS addons TEXT[];
+
+-- +migrate Down
+ALTER TABLE order_items DROP COLUMN IF EXISTS addons;
+ALTER TABLE order_items DROP COLUMN IF EXISTS condition_name;
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_name;
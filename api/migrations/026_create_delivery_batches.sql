@@ -0,0 +1,43 @@
+-- Couriers are users with role 'courier', they log in with the admin OTP flow
+COMMENT ON COLUMN users.role IS 'admin, courier or customer';
+
+-- Create delivery_batches table, one courier run on a delivery date
+CREATE TABLE IF NOT EXISTS delivery_batches (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    courier_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    delivery_date DATE,
+    notes TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_delivery_batches_date ON delivery_batches(delivery_date);
+CREATE INDEX IF NOT EXISTS idx_delivery_batches_courier ON delivery_batches(courier_id);
+
+-- Courier assignment and proof of delivery on orders
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS courier_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_batch_id UUID REFERENCES delivery_batches(id) ON DELETE SET NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_sequence INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivery_note TEXT;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS delivered_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_orders_courier ON orders(courier_id);
+CREATE INDEX IF NOT EXISTS idx_orders_delivery_batch ON orders(delivery_batch_id);
+
+COMMENT ON COLUMN orders.delivery_sequence IS 'Stop number on the route sheet of the delivery batch';
+COMMENT ON COLUMN orders.delivery_note IS 'Note left by the courier when completing the delivery';
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_orders_delivery_batch;
+DROP INDEX IF EXISTS idx_orders_courier;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS delivered_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS delivery_note;
+ALTER TABLE orders DROP COLUMN IF EXISTS delivery_sequence;
+ALTER TABLE orders DROP COLUMN IF EXISTS delivery_batch_id;
+ALTER TABLE orders DROP COLUMN IF EXISTS courier_id;
+
+DROP TABLE IF EXISTS delivery_batches;
+
+COMMENT ON COLUMN users.role IS NULL;
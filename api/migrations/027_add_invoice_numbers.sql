@@ -0,0 +1,17 @@
+-- Sequential invoice numbers, assigned the first time an order's invoice is printed
+CREATE SEQUENCE IF NOT EXISTS invoice_number_seq START 1;
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS invoice_number VARCHAR(32);
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS invoiced_at TIMESTAMP;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_invoice_number ON orders(invoice_number) WHERE invoice_number IS NOT NULL;
+
+COMMENT ON COLUMN orders.invoice_number IS 'INV-YYYYMM-NNNNN, never reused even if the order is edited later';
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_orders_invoice_number;
+
+ALTER TABLE orders DROP COLUMN IF EXISTS invoiced_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS invoice_number;
+
+DROP SEQUENCE IF EXISTS invoice_number_seq;
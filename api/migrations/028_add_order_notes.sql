@@ -0,0 +1,7 @@
+-- Customer notes for the kitchen, printed on the kitchen ticket
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS notes TEXT;
+
+COMMENT ON COLUMN orders.notes IS 'Notes from the customer, e.g. allergies or packing requests';
+
+-- +migrate Down
+ALTER TABLE orders DROP COLUMN IF EXISTS notes;
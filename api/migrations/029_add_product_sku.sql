@@ -0,0 +1,11 @@
+-- Stock keeping unit, the key for bulk product import and export
+ALTER TABLE products ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_sku ON products(sku) WHERE sku IS NOT NULL;
+
+COMMENT ON COLUMN products.sku IS 'Unique product code used to match rows of a product import';
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_products_sku;
+
+ALTER TABLE products DROP COLUMN IF EXISTS sku;
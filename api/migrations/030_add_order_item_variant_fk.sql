@@ -0,0 +1,17 @@
+-- Variants are updated in place and only deleted when removed from the
+-- product, so order items can reference them. Clear references to variants
+-- deleted before this constraint existed.
+UPDATE order_items SET variant_id = NULL
+WHERE variant_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM product_variants v WHERE v.id = order_items.variant_id);
+
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_variant_id_fkey;
+ALTER TABLE order_items ADD CONSTRAINT order_items_variant_id_fkey
+    FOREIGN KEY (variant_id) REFERENCES product_variants(id) ON DELETE SET NULL;
+
+COMMENT ON COLUMN order_items.variant_id IS 'Variant ordered, its stock is deducted instead of the product stock; cleared if the variant is deleted';
+
+-- +migrate Down
+COMMENT ON COLUMN order_items.variant_id IS NULL;
+
+ALTER TABLE order_items DROP CONSTRAINT IF EXISTS order_items_variant_id_fkey;
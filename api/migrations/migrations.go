@@ -0,0 +1,10 @@
+// Package migrations embeds the numbered SQL files of this directory so the
+// binary can apply them without the source tree.
+package migrations
+
+import "embed"
+
+// FS holds every *.sql migration, applied in file name order
+//
+//go:embed *.sql
+var FS embed.FS
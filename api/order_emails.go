@@ -0,0 +1,150 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/email"
+	"scaff-food-backend/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ==================== ORDER EMAILS ====================
+
+// Email template sent for each order event
+var orderEmailTemplates = map[string]string{
+	OrderEventCreated:          email.TemplateOrderConfirmation,
+	OrderEventPaymentConfirmed: email.TemplatePaymentReceived,
+	OrderEventOnDelivery:       email.TemplateOutForDelivery,
+	OrderEventCompleted:        email.TemplateOrderCompleted,
+	OrderEventCancelled:        email.TemplateOrderCancelled,
+}
+
+// Build template data for an order, formatting amounts and dates
+func buildOrderEmailData(order models.Order, items []models.OrderItem) email.OrderData {
+	data := email.OrderData{
+		CustomerName:        order.CustomerName,
+		OrderNumber:         order.OrderNumber,
+		DeliveryLocation:    order.DeliveryLocation,
+		PaymentMethod:       order.PaymentMethod,
+		DeliveryFee:         formatRupiah(order.DeliveryFee),
+		Total:               formatRupiah(order.Total),
+		AppreciationMessage: order.AppreciationMessage,
+		CancellationReason:  order.CancellationReason,
+	}
+	if order.DeliveryDate != nil {
+		data.DeliveryDate = order.DeliveryDate.Format("02-01-2006")
+	}
+	for _, item := range items {
+		data.Items = append(data.Items, email.OrderItemData{
+			Name:     item.ProductName,
+			Quantity: item.Quantity,
+			Subtotal: formatRupiah(item.Subtotal),
+		})
+	}
+	return data
+}
+
+// Queue the order event email, failures are only logged
+func sendOrderEmail(event string, order models.Order) {
+	name, ok := orderEmailTemplates[event]
+	if !ok || order.CustomerEmail == "" || !getSMTPConfig().Configured() {
+		return
+	}
+
+	var items []models.OrderItem
+	if db.Ready() {
+		db.DB.Where("order_id = ?", order.ID).Find(&items)
+	}
+
+	data := buildOrderEmailData(order, items)
+	// The tracking link is a bearer token, it is only sent once
+	if event == OrderEventCreated && order.TrackingToken != "" {
+		data.TrackingURL = trackingURL(order.TrackingToken)
+	}
+
+	rendered, err := email.Render(name, data)
+	if err != nil {
+		log.Printf("❌ Failed to render %s email for order %s: %v", name, order.OrderNumber, err)
+		return
+	}
+
+	if err := queueEmail(order.CustomerEmail, rendered); err != nil {
+		log.Printf("❌ Failed to queue %s email for order %s: %v", name, order.OrderNumber, err)
+		return
+	}
+	log.Printf("📧 Queued %s email for order %s", name, order.OrderNumber)
+}
+
+// Sample data used by the template preview
+func sampleEmailData(name string) interface{} {
+	switch name {
+	case email.TemplateOTP:
+		return email.OTPData{Code: "123456", ExpiresInMinutes: 5}
+	case email.TemplateStockAlert:
+		return email.StockAlertData{ProductName: "Udang Keju", VariantName: "3pcs", Stock: 4, Threshold: 5}
+	}
+
+	deliveryDate := time.Now().AddDate(0, 0, 1)
+	order := models.Order{
+		CustomerName:        "Budi Santoso",
+		OrderNumber:         "ORD-" + time.Now().Format("20060102") + "-001",
+		DeliveryLocation:    "TB",
+		DeliveryDate:        &deliveryDate,
+		PaymentMethod:       "qris",
+		DeliveryFee:         2000,
+		Total:               27000,
+		AppreciationMessage: "Terima kasih sudah jajan di SCAFF*FOOD, semoga harimu menyenangkan!",
+		CancellationReason:  "Stok bahan sedang habis",
+		TrackingToken:       "contoh-token",
+	}
+	items := []models.OrderItem{
+		{ProductName: "Cookies", Quantity: 3, Subtotal: 15000},
+		{ProductName: "Udang Keju 3pcs", Quantity: 1, Subtotal: 10000},
+	}
+	data := buildOrderEmailData(order, items)
+	if name == email.TemplateOrderConfirmation {
+		data.TrackingURL = trackingURL(order.TrackingToken)
+	}
+	return data
+}
+
+func registerEmailTemplateRoutes(app *fiber.App) {
+	// Admin: List email templates
+	app.Get("/api/admin/email-templates", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    email.Names(),
+		})
+	})
+
+	// Admin: Preview an email template with sample data (?format=html|text)
+	app.Get("/api/admin/email-templates/:name/preview", func(c *fiber.Ctx) error {
+		name := c.Params("name")
+		if !email.IsTemplate(name) {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Template not found",
+			})
+		}
+
+		rendered, err := email.Render(name, sampleEmailData(name))
+		if err != nil {
+			log.Printf("Error rendering email template %s: %v", name, err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to render template",
+			})
+		}
+
+		c.Set("X-Email-Subject", rendered.Subject)
+		if c.Query("format") == "text" {
+			c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+			return c.SendString(rendered.Text)
+		}
+		c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+		return c.SendString(rendered.HTML)
+	})
+}
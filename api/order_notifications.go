@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/notification"
+	"scaff-food-backend/internal/validate"
+)
+
+// ==================== ORDER NOTIFICATIONS ====================
+
+// Order lifecycle events that notify the customer
+const (
+	OrderEventCreated          = "order_created"
+	OrderEventPaymentConfirmed = "order_payment_confirmed"
+	OrderEventOnDelivery       = "order_on_delivery"
+	OrderEventCompleted        = "order_completed"
+	OrderEventCancelled        = "order_cancelled"
+)
+
+// Notifier used for customer messages, WhatsApp when configured
+var orderNotifier notification.Notifier = notification.LogNotifier{}
+
+// Only queue WhatsApp messages when a real channel is configured
+var whatsAppEnabled bool
+
+// Set up the WhatsApp notifier from the configuration
+func setupNotifier() {
+	whatsApp := appConfig.WhatsApp
+	if whatsApp.AccessToken == "" || whatsApp.PhoneNumberID == "" {
+		log.Println("⚠️  WhatsApp not configured, order notifications will only be logged")
+		return
+	}
+
+	orderNotifier = notification.NewWhatsAppNotifier(notification.WhatsAppConfig{
+		BaseURL:       whatsApp.APIURL,
+		APIVersion:    whatsApp.APIVersion,
+		PhoneNumberID: whatsApp.PhoneNumberID,
+		AccessToken:   whatsApp.AccessToken,
+		Language:      whatsApp.TemplateLanguage,
+	})
+	whatsAppEnabled = true
+	log.Println("✅ WhatsApp notifications enabled")
+}
+
+// Format an amount as Rupiah, e.g. Rp 15.000
+func formatRupiah(amount float64) string {
+	digits := fmt.Sprintf("%.0f", amount)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return "Rp " + b.String()
+}
+
+// Event for an order status, empty if the status has no notification
+func orderEventForStatus(status string) string {
+	switch status {
+	case "on_delivery":
+		return OrderEventOnDelivery
+	case "completed":
+		return OrderEventCompleted
+	case "cancelled", "dibatalkan":
+		return OrderEventCancelled
+	}
+	return ""
+}
+
+// Build the template message for an order event
+func buildOrderMessage(event string, order models.Order) notification.Message {
+	params := []string{order.CustomerName, order.OrderNumber}
+
+	switch event {
+	case OrderEventCreated:
+		deliveryDate := "-"
+		if order.DeliveryDate != nil {
+			deliveryDate = order.DeliveryDate.Format("02-01-2006")
+		}
+		params = append(params, formatRupiah(order.Total), deliveryDate)
+	case OrderEventPaymentConfirmed:
+		params = append(params, formatRupiah(order.Total))
+	case OrderEventCompleted:
+		message := order.AppreciationMessage
+		if message == "" {
+			message = "Terima kasih sudah memesan di SCAFF*FOOD!"
+		}
+		params = append(params, message)
+	case OrderEventCancelled:
+		params = append(params, order.CancellationReason)
+	}
+
+	return notification.Message{
+		To:       order.CustomerPhone,
+		Template: event,
+		Params:   params,
+	}
+}
+
+// Queue the order event for email and WhatsApp delivery,
+// failures are only logged
+func notifyOrderEvent(event string, order models.Order) {
+	if event == "" {
+		return
+	}
+	sendOrderEmail(event, order)
+
+	if !whatsAppEnabled {
+		return
+	}
+
+	phone, ok := validate.NormalizePhone(order.CustomerPhone)
+	if !ok {
+		log.Printf("⚠️ Skipping %s for order %s: invalid phone", event, order.OrderNumber)
+		return
+	}
+	order.CustomerPhone = phone
+
+	if err := queueWhatsApp(buildOrderMessage(event, order)); err != nil {
+		log.Printf("❌ Failed to queue %s for order %s: %v", event, order.OrderNumber, err)
+		return
+	}
+	log.Printf("📨 Queued %s for order %s", event, order.OrderNumber)
+}
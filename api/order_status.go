@@ -0,0 +1,59 @@
+package main
+
+import (
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/realtime"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ==================== ORDER STATUS CHANGES ====================
+
+// Side effects of a new order: stock, realtime events and customer
+// notifications
+func afterOrderCreated(order models.Order, items []models.OrderItem) {
+	applyOrderStock(order, items, MovementSale, "order")
+
+	publishOrderEvent(realtime.EventOrderCreated, order, nil)
+
+	notifyOrderEvent(OrderEventCreated, order)
+	if order.PaymentStatus == "paid" {
+		notifyOrderEvent(OrderEventPaymentConfirmed, order)
+	}
+}
+
+// Side effects of an order status or payment change: costing, stock,
+// realtime events and customer notifications. completionUpdated marks a
+// new delivery photo or message on a completed order.
+func afterOrderStatusChange(order models.Order, oldStatus, oldPaymentStatus string, completionUpdated bool, actor string) {
+	// Freeze the cost of goods sold when the order is completed
+	if order.OrderStatus == "completed" && oldStatus != "completed" {
+		computeOrderCOGS(order.ID)
+	}
+
+	// Cancelling returns the items to stock, reopening takes them again
+	if models.IsCancelledStatus(order.OrderStatus) != models.IsCancelledStatus(oldStatus) {
+		var items []models.OrderItem
+		db.DB.Where("order_id = ?", order.ID).Find(&items)
+		if models.IsCancelledStatus(order.OrderStatus) {
+			applyOrderStock(order, items, MovementCancelReturn, actor)
+		} else {
+			applyOrderStock(order, items, MovementSale, actor)
+		}
+	}
+
+	if order.OrderStatus != oldStatus || completionUpdated {
+		publishOrderEvent(realtime.EventOrderStatusChanged, order, fiber.Map{"previous_status": oldStatus})
+	}
+	if order.PaymentStatus != oldPaymentStatus {
+		publishOrderEvent(realtime.EventPaymentUpdated, order, fiber.Map{"previous_payment_status": oldPaymentStatus})
+	}
+
+	if order.PaymentStatus == "paid" && oldPaymentStatus != "paid" {
+		notifyOrderEvent(OrderEventPaymentConfirmed, order)
+	}
+	if order.OrderStatus != oldStatus {
+		notifyOrderEvent(orderEventForStatus(order.OrderStatus), order)
+	}
+}
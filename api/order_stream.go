@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/realtime"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ==================== ORDER STREAM ====================
+
+// Events are kept this long for Last-Event-ID replay
+const orderEventRetention = 7 * 24 * time.Hour
+
+// Heartbeat interval, keeps proxies from closing idle streams
+const orderStreamHeartbeat = 15 * time.Second
+
+var orderHub = realtime.NewHub()
+
+// Start forwarding order events from Postgres to the stream hub
+func setupOrderStream() {
+	realtime.Listen(context.Background(), appConfig.Database.DSN(), db.Get, orderHub)
+}
+
+// Record an order event for the stream and webhooks, failures are only logged
+func publishOrderEvent(eventType string, order models.Order, extra fiber.Map) {
+	if !db.Ready() {
+		return
+	}
+
+	data := fiber.Map{
+		"order_id":       order.ID,
+		"order_number":   order.OrderNumber,
+		"customer_name":  order.CustomerName,
+		"order_status":   order.OrderStatus,
+		"payment_status": order.PaymentStatus,
+		"total":          order.Total,
+	}
+	for k, v := range extra {
+		data[k] = v
+	}
+
+	if _, err := realtime.Publish(db.DB, eventType, order.ID, data); err != nil {
+		log.Printf("❌ Failed to publish %s for order %s: %v", eventType, order.OrderNumber, err)
+	}
+
+	dispatchWebhooks(eventType, data, orderWebhookSummary(eventType, order))
+}
+
+// Delete order events past the replay window
+func purgeOrderEvents() {
+	if !db.Ready() {
+		return
+	}
+	db.DB.Where("created_at < ?", time.Now().Add(-orderEventRetention)).Delete(&realtime.Event{})
+}
+
+// Write one event in SSE format
+func writeSSEEvent(w *bufio.Writer, event realtime.Event) error {
+	fmt.Fprintf(w, "id: %d\n", event.ID)
+	fmt.Fprintf(w, "event: %s\n", event.Type)
+	for _, line := range strings.Split(event.Data, "\n") {
+		fmt.Fprintf(w, "data: %s\n", line)
+	}
+	w.WriteString("\n")
+	return w.Flush()
+}
+
+// Last event id the client saw, from the header browsers send on reconnect
+// or ?last_event_id= for the first connection; -1 if none
+func lastEventID(c *fiber.Ctx) int64 {
+	value := c.Get("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id < 0 {
+		return -1
+	}
+	return id
+}
+
+// orderStream configures one SSE connection
+type orderStream struct {
+	// Events to send, nil sends everything
+	Filter func(realtime.Event) bool
+	// Replay events after this id first, -1 to only send new events
+	LastID int64
+	// Optional event sent before anything else
+	Snapshot *realtime.Event
+	// Optional rewrite of each event before it is sent, false skips it
+	Transform func(realtime.Event) (realtime.Event, bool)
+}
+
+// Stream events to the client until it disconnects. The subscription is
+// taken before the replay is read so nothing falls between the two.
+func streamOrderEvents(c *fiber.Ctx, stream orderStream) error {
+	sub := orderHub.Subscribe(stream.Filter)
+	lastID := stream.LastID
+
+	var backlog []realtime.Event
+	if lastID >= 0 {
+		for {
+			events, err := realtime.After(db.DB, lastID, 500)
+			if err != nil {
+				orderHub.Unsubscribe(sub)
+				log.Printf("Error fetching order events: %v", err)
+				return c.Status(500).JSON(fiber.Map{
+					"success": false,
+					"message": "Failed to fetch order events",
+				})
+			}
+			for _, event := range events {
+				if stream.Filter == nil || stream.Filter(event) {
+					backlog = append(backlog, event)
+				}
+				lastID = event.ID
+			}
+			if len(events) < 500 {
+				break
+			}
+		}
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	send := func(w *bufio.Writer, event realtime.Event) error {
+		if stream.Transform != nil {
+			var ok bool
+			if event, ok = stream.Transform(event); !ok {
+				return nil
+			}
+		}
+		return writeSSEEvent(w, event)
+	}
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer orderHub.Unsubscribe(sub)
+
+		w.WriteString("retry: 3000\n\n")
+		if stream.Snapshot != nil {
+			writeSSEEvent(w, *stream.Snapshot)
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for _, event := range backlog {
+			if err := send(w, event); err != nil {
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(orderStreamHeartbeat)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event := <-sub.Events:
+				// Already sent as part of the backlog
+				if event.ID <= lastID {
+					continue
+				}
+				if err := send(w, event); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				w.WriteString(": ping\n\n")
+				if err := w.Flush(); err != nil {
+					return
+				}
+			case <-sub.Overflow:
+				// Fell behind, the client reconnects and resumes from its last id
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
+func registerOrderStreamRoutes(app *fiber.App) {
+	// Admin: Token for opening the stream with EventSource, which cannot send
+	// the Authorization header: /api/admin/orders/stream?stream_token=...
+	// It works for a minute, reconnecting after that needs a new one.
+	app.Post("/api/admin/orders/stream-token", func(c *fiber.Ctx) error {
+		session := auth.Current(c)
+		token := auth.StreamTokens.Create(session.UserID, session.Email, session.Role, auth.StreamTokenTTL)
+		return c.JSON(fiber.Map{
+			"success":    true,
+			"token":      token,
+			"expires_in": int(auth.StreamTokenTTL.Seconds()),
+		})
+	})
+
+	// Admin: Live order events (SSE), resumes with Last-Event-ID
+	app.Get("/api/admin/orders/stream", func(c *fiber.Ctx) error {
+		return streamOrderEvents(c, orderStream{LastID: lastEventID(c)})
+	})
+}
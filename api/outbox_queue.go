@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/email"
+	"scaff-food-backend/internal/notification"
+	"scaff-food-backend/internal/outbox"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ==================== OUTBOX ====================
+
+// Outbox channels
+const (
+	OutboxChannelEmail    = "email"
+	OutboxChannelWhatsApp = "whatsapp"
+)
+
+var outboxQueue *outbox.Outbox
+
+// Set up the outbox workers for email and WhatsApp delivery
+func setupOutbox() {
+	outboxQueue = outbox.New(db.Get, outbox.Config{
+		Workers:     appConfig.Outbox.Workers,
+		MaxAttempts: appConfig.Outbox.MaxAttempts,
+	})
+
+	outboxQueue.Handle(OutboxChannelEmail, func(ctx context.Context, msg outbox.Message) error {
+		var rendered email.Rendered
+		if err := json.Unmarshal([]byte(msg.Payload), &rendered); err != nil {
+			return err
+		}
+		return email.Send(ctx, getSMTPConfig(), msg.Recipient, &rendered)
+	})
+
+	outboxQueue.Handle(OutboxChannelWhatsApp, func(ctx context.Context, msg outbox.Message) error {
+		var message notification.Message
+		if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+			return err
+		}
+		return orderNotifier.Send(ctx, message)
+	})
+
+	outboxQueue.Handle(OutboxChannelWebhook, handleWebhookDelivery)
+
+	outboxQueue.Start(context.Background())
+}
+
+// Queue a rendered email for delivery
+func queueEmail(to string, rendered *email.Rendered) error {
+	_, err := outboxQueue.Enqueue(OutboxChannelEmail, to, rendered)
+	return err
+}
+
+// OTP codes expire within minutes, their payload is never shown to admins
+// and a failed one is not sent again. Emails queued before the template was
+// recorded cannot be told apart.
+func isOTPEmail(msg outbox.Message) bool {
+	if msg.Channel != OutboxChannelEmail {
+		return false
+	}
+	var rendered email.Rendered
+	if err := json.Unmarshal([]byte(msg.Payload), &rendered); err != nil {
+		return false
+	}
+	return rendered.Template == email.TemplateOTP
+}
+
+// Queue a WhatsApp message for delivery
+func queueWhatsApp(msg notification.Message) error {
+	_, err := outboxQueue.Enqueue(OutboxChannelWhatsApp, msg.To, msg)
+	return err
+}
+
+func registerOutboxRoutes(app *fiber.App) {
+	// Admin: List outbox messages (?status=dead&limit=50)
+	app.Get("/api/admin/outbox", func(c *fiber.Ctx) error {
+		status := c.Query("status")
+		if status != "" && status != outbox.StatusPending && status != outbox.StatusProcessing &&
+			status != outbox.StatusSent && status != outbox.StatusDead {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid status",
+			})
+		}
+
+		limit := c.QueryInt("limit", 50)
+		if limit < 1 || limit > 500 {
+			limit = 50
+		}
+
+		messages, err := outboxQueue.List(status, limit)
+		if err != nil {
+			log.Printf("Error fetching outbox messages: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to fetch outbox messages",
+			})
+		}
+
+		// Payloads can hold OTP codes, only show them for failed messages
+		// that are not OTP emails
+		for i := range messages {
+			if messages[i].Status != outbox.StatusDead || isOTPEmail(messages[i]) {
+				messages[i].Payload = ""
+			}
+		}
+
+		counts, _ := outboxQueue.Counts()
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    messages,
+			"counts":  counts,
+		})
+	})
+
+	// Admin: Retry a failed outbox message
+	app.Post("/api/admin/outbox/:id/retry", func(c *fiber.Ctx) error {
+		existing, err := outboxQueue.Get(c.Params("id"))
+		if err == nil && isOTPEmail(*existing) {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "OTP emails are not retried, the code has expired",
+			})
+		}
+
+		msg, err := outboxQueue.Retry(c.Params("id"))
+		if err == gorm.ErrRecordNotFound {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Message not found or not retryable",
+			})
+		}
+		if err != nil {
+			log.Printf("Error retrying outbox message: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to retry message",
+			})
+		}
+
+		log.Printf("🔁 Outbox message %s queued for retry", msg.ID)
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    msg,
+			"message": "Message queued for retry",
+		})
+	})
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"scaff-food-backend/internal/email"
+	"scaff-food-backend/internal/outbox"
+)
+
+func TestIsOTPEmail(t *testing.T) {
+	payload := func(template string) string {
+		encoded, _ := json.Marshal(email.Rendered{Template: template, Subject: "Kode", Text: "123456"})
+		return string(encoded)
+	}
+
+	tests := []struct {
+		name string
+		msg  outbox.Message
+		want bool
+	}{
+		{"otp email", outbox.Message{Channel: OutboxChannelEmail, Payload: payload(email.TemplateOTP)}, true},
+		{"order email", outbox.Message{Channel: OutboxChannelEmail, Payload: payload(email.TemplateOrderConfirmation)}, false},
+		{"queued before templates were recorded", outbox.Message{Channel: OutboxChannelEmail, Payload: `{"Subject":"Kode"}`}, false},
+		{"whatsapp", outbox.Message{Channel: OutboxChannelWhatsApp, Payload: payload(email.TemplateOTP)}, false},
+	}
+	for _, test := range tests {
+		if got := isOTPEmail(test.msg); got != test.want {
+			t.Errorf("%s: isOTPEmail() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
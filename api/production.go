@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/pdf"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+)
+
+// ==================== PRODUCTION PLAN ====================
+
+// ProductionLine is the total quantity of one product with one set of options
+type ProductionLine struct {
+	ProductID   string   `json:"product_id"`
+	ProductName string   `json:"product_name"`
+	Variant     string   `json:"variant,omitempty"`
+	Condition   string   `json:"condition,omitempty"`
+	Addons      []string `json:"addons,omitempty"`
+	Quantity    int      `json:"quantity"`
+	Orders      int      `json:"orders"`
+}
+
+// ProductionLocation is the plan for one delivery location
+type ProductionLocation struct {
+	Location      string           `json:"location"`
+	Orders        int              `json:"orders"`
+	TotalQuantity int              `json:"total_quantity"`
+	Lines         []ProductionLine `json:"lines"`
+}
+
+// ProductionPlan is what the kitchen makes for one delivery date
+type ProductionPlan struct {
+	Date          string               `json:"date"`
+	Orders        int                  `json:"orders"`
+	TotalQuantity int                  `json:"total_quantity"`
+	Locations     []ProductionLocation `json:"locations"`
+	Totals        []ProductionLine     `json:"totals"`
+}
+
+// One order item with what is needed to group it
+type productionRow struct {
+	OrderID          string
+	DeliveryLocation string
+	ProductID        string
+	ProductName      string
+	BaseName         string
+	VariantName      string
+	VariantLookup    string
+	ConditionName    string
+	Addons           pq.StringArray `gorm:"type:text[]"`
+	Quantity         int
+}
+
+// Split variant and condition out of an item name built by the order page,
+// "<product> (<variant>) - <condition>", for items without structured options
+func splitLegacyItemName(itemName, baseName string) (variant, condition string) {
+	if baseName == "" || !strings.HasPrefix(itemName, baseName) {
+		return "", ""
+	}
+	rest := itemName[len(baseName):]
+	if strings.HasPrefix(rest, " (") {
+		if end := strings.Index(rest, ")"); end > 2 {
+			variant = rest[2:end]
+			rest = rest[end+1:]
+		}
+	}
+	if strings.HasPrefix(rest, " - ") {
+		condition = strings.TrimSpace(rest[3:])
+	}
+	return variant, condition
+}
+
+// Group lines by product and options, sorted for the prep sheet
+type productionGroup struct {
+	lines  map[string]*ProductionLine
+	orders map[string]map[string]bool
+}
+
+func newProductionGroup() *productionGroup {
+	return &productionGroup{
+		lines:  make(map[string]*ProductionLine),
+		orders: make(map[string]map[string]bool),
+	}
+}
+
+func (g *productionGroup) add(orderID string, line ProductionLine) {
+	key := strings.Join([]string{line.ProductID, line.ProductName, line.Variant, line.Condition, strings.Join(line.Addons, "|")}, "\x00")
+	existing, ok := g.lines[key]
+	if !ok {
+		copied := line
+		copied.Quantity = 0
+		existing = &copied
+		g.lines[key] = existing
+		g.orders[key] = make(map[string]bool)
+	}
+	existing.Quantity += line.Quantity
+	g.orders[key][orderID] = true
+	existing.Orders = len(g.orders[key])
+}
+
+func (g *productionGroup) sorted() []ProductionLine {
+	lines := make([]ProductionLine, 0, len(g.lines))
+	for _, line := range g.lines {
+		lines = append(lines, *line)
+	}
+	sort.Slice(lines, func(i, j int) bool {
+		a, b := lines[i], lines[j]
+		if a.ProductName != b.ProductName {
+			return a.ProductName < b.ProductName
+		}
+		if a.Variant != b.Variant {
+			return a.Variant < b.Variant
+		}
+		if a.Condition != b.Condition {
+			return a.Condition < b.Condition
+		}
+		return strings.Join(a.Addons, ",") < strings.Join(b.Addons, ",")
+	})
+	return lines
+}
+
+// Build the production plan of the active orders delivered on date
+func buildProductionPlan(date string) (*ProductionPlan, error) {
+	var rows []productionRow
+	err := db.DB.Table("order_items oi").
+		Select(`o.id AS order_id, o.delivery_location, oi.product_id, oi.product_name,
+			COALESCE(p.name, '') AS base_name, oi.variant_name, COALESCE(v.name, '') AS variant_lookup,
+			oi.condition_name, oi.addons, oi.quantity`).
+		Joins("JOIN orders o ON o.id = oi.order_id").
+		Joins("LEFT JOIN products p ON p.id = oi.product_id").
+		Joins("LEFT JOIN product_variants v ON v.id = oi.variant_id").
+		Where("o.order_status IN ? AND o.delivery_date = ?", models.ActiveOrderStatuses, date).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &ProductionPlan{Date: date, Locations: []ProductionLocation{}, Totals: []ProductionLine{}}
+	totals := newProductionGroup()
+	byLocation := make(map[string]*productionGroup)
+	locationOrders := make(map[string]map[string]bool)
+	allOrders := make(map[string]bool)
+
+	for _, row := range rows {
+		line := ProductionLine{
+			ProductID: row.ProductID,
+			Variant:   row.VariantName,
+			Condition: row.ConditionName,
+			Addons:    row.Addons,
+			Quantity:  row.Quantity,
+		}
+		if line.Variant == "" {
+			line.Variant = row.VariantLookup
+		}
+
+		// Structured options keep the product name clean, older items only
+		// have them in the name
+		line.ProductName = row.BaseName
+		if line.ProductName == "" {
+			line.ProductName = row.ProductName
+		} else if line.Variant == "" && line.Condition == "" && row.ProductName != row.BaseName {
+			line.Variant, line.Condition = splitLegacyItemName(row.ProductName, row.BaseName)
+			if line.Variant == "" && line.Condition == "" {
+				line.ProductName = row.ProductName
+			}
+		}
+
+		location := row.DeliveryLocation
+		if location == "" {
+			location = "TB"
+		}
+		if byLocation[location] == nil {
+			byLocation[location] = newProductionGroup()
+			locationOrders[location] = make(map[string]bool)
+		}
+		byLocation[location].add(row.OrderID, line)
+		totals.add(row.OrderID, line)
+		locationOrders[location][row.OrderID] = true
+		allOrders[row.OrderID] = true
+		plan.TotalQuantity += row.Quantity
+	}
+
+	locations := make([]string, 0, len(byLocation))
+	for location := range byLocation {
+		locations = append(locations, location)
+	}
+	sort.Strings(locations)
+
+	for _, location := range locations {
+		section := ProductionLocation{
+			Location: location,
+			Orders:   len(locationOrders[location]),
+			Lines:    byLocation[location].sorted(),
+		}
+		for _, line := range section.Lines {
+			section.TotalQuantity += line.Quantity
+		}
+		plan.Locations = append(plan.Locations, section)
+	}
+	plan.Totals = totals.sorted()
+	plan.Orders = len(allOrders)
+
+	return plan, nil
+}
+
+// Production plan as CSV, one row per location and line plus the totals
+func productionPlanCSV(plan *ProductionPlan) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString("\ufeff") // Excel needs the BOM to read UTF-8
+
+	w := csv.NewWriter(&buf)
+	w.Write([]string{"Tanggal Kirim", "Lokasi", "Produk", "Varian", "Kondisi", "Addon", "Jumlah", "Pesanan"})
+	write := func(location string, line ProductionLine) {
+		w.Write([]string{
+			plan.Date,
+			location,
+			line.ProductName,
+			line.Variant,
+			line.Condition,
+			strings.Join(line.Addons, ", "),
+			strconv.Itoa(line.Quantity),
+			strconv.Itoa(line.Orders),
+		})
+	}
+	for _, section := range plan.Locations {
+		for _, line := range section.Lines {
+			write(section.Location, line)
+		}
+	}
+	for _, line := range plan.Totals {
+		write("Total", line)
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// Production plan as a printable A4 prep sheet
+func productionPlanPDF(plan *ProductionPlan) []byte {
+	const (
+		margin     = 40.0
+		rowHeight  = 18.0
+		fontSize   = 10.0
+		colProduct = margin + 22
+		colVariant = margin + 205
+		colOptions = margin + 320
+		colQty     = 555.0
+	)
+
+	doc := pdf.New()
+	var page *pdf.Page
+	y := 0.0
+
+	newPage := func() {
+		page = doc.AddPage()
+		page.Text(margin, 50, 18, true, "Rencana Produksi SCAFF*FOOD")
+		page.Text(margin, 68, 10, false, fmt.Sprintf("Tanggal kirim: %s   |   %d pesanan   |   %d item", plan.Date, plan.Orders, plan.TotalQuantity))
+		page.TextRight(colQty, 68, 8, false, fmt.Sprintf("Dicetak %s  -  hal. %d", time.Now().Format("02-01-2006 15:04"), doc.PageCount()))
+		page.Line(margin, 76, colQty, 76, 1)
+		y = 100
+	}
+	ensure := func(height float64) {
+		if page == nil || y+height > doc.Height-margin {
+			newPage()
+		}
+	}
+
+	section := func(title string, lines []ProductionLine) {
+		ensure(rowHeight * 3)
+		page.Text(margin, y, 13, true, title)
+		y += 8
+		page.FillRect(margin, y, colQty-margin, rowHeight, 0.9)
+		page.Text(colProduct, y+12.5, 9, true, "Produk")
+		page.Text(colVariant, y+12.5, 9, true, "Varian")
+		page.Text(colOptions, y+12.5, 9, true, "Kondisi / Addon")
+		page.TextRight(colQty-4, y+12.5, 9, true, "Jumlah")
+		y += rowHeight
+
+		for _, line := range lines {
+			ensure(rowHeight)
+			// Checkbox to tick off while cooking
+			page.StrokeRect(margin+4, y+4, 10, 10, 0.7)
+			page.Text(colProduct, y+12.5, fontSize, false, pdf.Fit(line.ProductName, fontSize, false, colVariant-colProduct-6))
+			page.Text(colVariant, y+12.5, fontSize, false, pdf.Fit(line.Variant, fontSize, false, colOptions-colVariant-6))
+			options := line.Condition
+			if len(line.Addons) > 0 {
+				if options != "" {
+					options += " + "
+				}
+				options += strings.Join(line.Addons, ", ")
+			}
+			page.Text(colOptions, y+12.5, fontSize, false, pdf.Fit(options, fontSize, false, colQty-colOptions-50))
+			page.TextRight(colQty-4, y+12.5, fontSize, true, strconv.Itoa(line.Quantity))
+			page.Line(margin, y+rowHeight, colQty, y+rowHeight, 0.3)
+			y += rowHeight
+		}
+		y += 22
+	}
+
+	if len(plan.Totals) == 0 {
+		ensure(rowHeight)
+		page.Text(margin, y, 11, false, "Tidak ada pesanan aktif untuk tanggal ini.")
+	}
+	for _, loc := range plan.Locations {
+		section(fmt.Sprintf("Lokasi %s  (%d pesanan, %d item)", loc.Location, loc.Orders, loc.TotalQuantity), loc.Lines)
+	}
+	if len(plan.Locations) > 1 {
+		section("Total Semua Lokasi", plan.Totals)
+	}
+
+	return doc.Bytes()
+}
+
+func registerProductionRoutes(app *fiber.App) {
+	// Admin: Kitchen production plan for a delivery date (?date=YYYY-MM-DD&format=json|csv|pdf)
+	app.Get("/api/admin/production", func(c *fiber.Ctx) error {
+		date := c.Query("date", time.Now().Format("2006-01-02"))
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Date must be in YYYY-MM-DD format",
+			})
+		}
+
+		plan, err := buildProductionPlan(date)
+		if err != nil {
+			log.Printf("Error building production plan: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to build production plan",
+			})
+		}
+
+		switch c.Query("format", "json") {
+		case "csv":
+			data, err := productionPlanCSV(plan)
+			if err != nil {
+				log.Printf("Error writing production CSV: %v", err)
+				return c.Status(500).JSON(fiber.Map{
+					"success": false,
+					"message": "Failed to build production plan",
+				})
+			}
+			c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+			c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="produksi-%s.csv"`, date))
+			return c.Send(data)
+		case "pdf":
+			c.Set(fiber.HeaderContentType, "application/pdf")
+			c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="produksi-%s.pdf"`, date))
+			return c.Send(productionPlanPDF(plan))
+		case "json":
+			return c.JSON(fiber.Map{
+				"success": true,
+				"data":    plan,
+			})
+		default:
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Format must be json, csv or pdf",
+			})
+		}
+	})
+}
@@ -0,0 +1,674 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/validate"
+	"scaff-food-backend/internal/xlsx"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// ==================== PRODUCT IMPORT & EXPORT ====================
+
+// Columns of the product sheet, the export writes them in this order and
+// the import matches them by header name.
+//
+// List cells are separated by ";":
+//   - conditions: "Pedas; Extra Pedas=2000" (name=price_adjustment)
+//   - addons:     "Extra Keju=5000; Saos=2000" (name=price)
+//   - variants:   "Reguler=15000:10; Jumbo=20000" (name=price:stock, stock
+//     defaults to 100 like the product form)
+//   - days:       "monday,tuesday" or Indonesian names, empty means every day
+var productSheetColumns = []string{
+	"sku", "name", "category", "price", "short_description", "description",
+	"tag", "tag_color", "image_url_1", "image_url_2", "image_url_3",
+	"stock", "low_stock_threshold", "is_available",
+	"min_order_tb", "min_order_luar_tb", "available_days_tb", "available_days_luar_tb",
+	"conditions", "addons", "variants",
+}
+
+// Import modes
+const (
+	ImportModeCreate = "create"
+	ImportModeUpsert = "upsert"
+)
+
+const maxProductImportRows = 2000
+
+var weekDays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+var indonesianWeekDays = map[string]string{
+	"senin": "monday", "selasa": "tuesday", "rabu": "wednesday", "kamis": "thursday",
+	"jumat": "friday", "jum'at": "friday", "sabtu": "saturday", "minggu": "sunday",
+}
+
+var thousandsPattern = regexp.MustCompile(`^\d{1,3}(\.\d{3})+$`)
+
+// productImportRow is the outcome of one sheet row
+type productImportRow struct {
+	Row    int      `json:"row"`
+	SKU    string   `json:"sku,omitempty"`
+	Name   string   `json:"name"`
+	Action string   `json:"action"`
+	Errors []string `json:"errors,omitempty"`
+
+	product    models.Product
+	variants   []models.ProductVariant
+	existingID string
+	// Cells that were empty or columns missing from the file
+	blank map[string]bool
+}
+
+// productImportReport is returned by dry runs and imports alike
+type productImportReport struct {
+	DryRun  bool               `json:"dry_run"`
+	Mode    string             `json:"mode"`
+	Total   int                `json:"total"`
+	Created int                `json:"created"`
+	Updated int                `json:"updated"`
+	Failed  int                `json:"failed"`
+	Rows    []productImportRow `json:"rows"`
+}
+
+// Read the uploaded sheet, XLSX by its zip signature, CSV otherwise
+func readProductSheet(data []byte) ([][]string, error) {
+	if bytes.HasPrefix(data, []byte("PK")) {
+		return xlsx.ReadRows(bytes.NewReader(data), int64(len(data)), maxProductImportRows+2)
+	}
+
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	// Spreadsheets in an Indonesian locale save CSV with semicolons
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		r.Comma = ';'
+	}
+
+	var rows [][]string
+	// Header plus one row more than allowed, to tell the file is too long
+	for len(rows) < maxProductImportRows+2 {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, record)
+	}
+	return rows, nil
+}
+
+// Parse an amount like "15000", "15.000", "Rp 15.000" or "15000.50"
+func parseAmount(s string) (float64, error) {
+	s = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "Rp"))
+	s = strings.ReplaceAll(s, " ", "")
+	if thousandsPattern.MatchString(s) {
+		s = strings.ReplaceAll(s, ".", "")
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("%q is not a valid amount", s)
+	}
+	return value, nil
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "true", "yes", "ya", "1", "y":
+		return true, nil
+	case "false", "no", "tidak", "0", "n":
+		return false, nil
+	}
+	return false, fmt.Errorf("%q is not true or false", s)
+}
+
+// Parse "monday,tuesday", empty or "all" means every day
+func parseDays(s string) (pq.StringArray, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" || s == "all" || s == "semua" {
+		return pq.StringArray(append([]string(nil), weekDays...)), nil
+	}
+	var days pq.StringArray
+	seen := make(map[string]bool)
+	for _, day := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ';' || r == ' ' }) {
+		if english, ok := indonesianWeekDays[day]; ok {
+			day = english
+		}
+		valid := false
+		for _, d := range weekDays {
+			if d == day {
+				valid = true
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("%q is not a day of the week", day)
+		}
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+// Split "a=1; b=2" into names and the text after the last "="
+func parseOptionList(s string) ([][2]string, error) {
+	var options [][2]string
+	for _, part := range strings.Split(s, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value := part, ""
+		if i := strings.LastIndex(part, "="); i >= 0 {
+			name, value = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		}
+		if name == "" {
+			return nil, fmt.Errorf("%q has no name", part)
+		}
+		options = append(options, [2]string{name, value})
+	}
+	return options, nil
+}
+
+// Parse "Pedas; Extra Pedas=2000" into the conditions JSON
+func parseConditions(s string) (string, error) {
+	options, err := parseOptionList(s)
+	if err != nil {
+		return "", err
+	}
+	conditions := make([]map[string]interface{}, 0, len(options))
+	for _, o := range options {
+		adjustment := 0.0
+		if o[1] != "" {
+			if adjustment, err = parseAmount(o[1]); err != nil {
+				return "", err
+			}
+		}
+		conditions = append(conditions, map[string]interface{}{"name": o[0], "price_adjustment": adjustment})
+	}
+	data, err := json.Marshal(conditions)
+	return string(data), err
+}
+
+// Parse "Extra Keju=5000; Saos=2000" into the addons JSON
+func parseAddons(s string) (string, error) {
+	options, err := parseOptionList(s)
+	if err != nil {
+		return "", err
+	}
+	addons := make([]map[string]interface{}, 0, len(options))
+	for _, o := range options {
+		price := 0.0
+		if o[1] != "" {
+			if price, err = parseAmount(o[1]); err != nil {
+				return "", err
+			}
+		}
+		addons = append(addons, map[string]interface{}{"name": o[0], "price": price})
+	}
+	data, err := json.Marshal(addons)
+	return string(data), err
+}
+
+// Parse "Reguler=15000:10; Jumbo=20000" into variants
+func parseVariants(s string) ([]models.ProductVariant, error) {
+	options, err := parseOptionList(s)
+	if err != nil {
+		return nil, err
+	}
+	variants := make([]models.ProductVariant, 0, len(options))
+	seen := make(map[string]bool)
+	for _, o := range options {
+		if seen[o[0]] {
+			return nil, fmt.Errorf("variant %q is listed twice", o[0])
+		}
+		seen[o[0]] = true
+
+		priceText, stockText, hasStock := strings.Cut(o[1], ":")
+		if strings.TrimSpace(priceText) == "" {
+			return nil, fmt.Errorf("variant %q needs a price", o[0])
+		}
+		price, err := parseAmount(priceText)
+		if err != nil {
+			return nil, fmt.Errorf("variant %q: %v", o[0], err)
+		}
+		variant := models.ProductVariant{Name: o[0], Price: price, IsAvailable: true}
+		if hasStock && strings.TrimSpace(stockText) != "" {
+			if variant.Stock, err = strconv.Atoi(strings.TrimSpace(stockText)); err != nil || variant.Stock < 0 {
+				return nil, fmt.Errorf("variant %q: %q is not a valid stock", o[0], stockText)
+			}
+		}
+		variants = append(variants, variant)
+	}
+	return variants, nil
+}
+
+// Column index by name from the header row, "Short Description" matches
+// short_description
+func productSheetHeader(header []string) map[string]int {
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		key = strings.ReplaceAll(key, " ", "_")
+		if key != "" {
+			columns[key] = i
+		}
+	}
+	return columns
+}
+
+// Parse and validate the sheet rows, nothing is written
+func buildProductImport(rows [][]string, mode string) (*productImportReport, string) {
+	if len(rows) == 0 {
+		return nil, "The file is empty"
+	}
+	if len(rows)-1 > maxProductImportRows {
+		return nil, fmt.Sprintf("The file has more than %d products", maxProductImportRows)
+	}
+
+	columns := productSheetHeader(rows[0])
+	for _, required := range []string{"name", "price"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Sprintf("Missing column %q, expected columns: %s", required, strings.Join(productSheetColumns, ", "))
+		}
+	}
+
+	// Existing SKUs for upserts and duplicate checks
+	existing := make(map[string]string)
+	var skuRows []struct {
+		ID  string
+		SKU string
+	}
+	db.DB.Model(&models.Product{}).Select("id, sku").Where("sku IS NOT NULL").Scan(&skuRows)
+	for _, r := range skuRows {
+		existing[r.SKU] = r.ID
+	}
+
+	report := &productImportReport{Mode: mode, Rows: []productImportRow{}}
+	seenSKU := make(map[string]int)
+
+	for i, record := range rows[1:] {
+		cell := func(name string) (string, bool) {
+			index, ok := columns[name]
+			if !ok || index >= len(record) {
+				return "", false
+			}
+			value := strings.TrimSpace(record[index])
+			return value, value != ""
+		}
+
+		empty := true
+		for _, value := range record {
+			if strings.TrimSpace(value) != "" {
+				empty = false
+				break
+			}
+		}
+		if empty {
+			continue
+		}
+
+		row := productImportRow{Row: i + 2, Action: "create", blank: make(map[string]bool)}
+		fail := func(format string, args ...interface{}) {
+			row.Errors = append(row.Errors, fmt.Sprintf(format, args...))
+		}
+		for _, name := range productSheetColumns {
+			if _, ok := cell(name); !ok {
+				row.blank[name] = true
+			}
+		}
+		p := &row.product
+
+		row.Name, _ = cell("name")
+		p.Name = validate.SanitizeString(row.Name)
+		if p.Name == "" {
+			fail("name is required")
+		} else if len(p.Name) > 255 {
+			fail("name is longer than 255 characters")
+		}
+
+		if sku, ok := cell("sku"); ok {
+			row.SKU = sku
+			p.SKU = &sku
+			if len(sku) > 64 {
+				fail("sku is longer than 64 characters")
+			}
+			if first, dup := seenSKU[sku]; dup {
+				fail("sku %s is already used on row %d", sku, first)
+			}
+			seenSKU[sku] = row.Row
+			if id, ok := existing[sku]; ok {
+				if mode == ImportModeUpsert {
+					row.Action = "update"
+					row.existingID = id
+				} else {
+					fail("sku %s already exists, import with mode=upsert to update it", sku)
+				}
+			}
+		}
+
+		if price, ok := cell("price"); !ok {
+			fail("price is required")
+		} else if value, err := parseAmount(price); err != nil {
+			fail("price: %v", err)
+		} else {
+			p.Price = value
+		}
+
+		text := func(name string, dest *string) {
+			value, _ := cell(name)
+			*dest = validate.SanitizeString(value)
+		}
+		text("category", &p.Category)
+		text("short_description", &p.ShortDescription)
+		text("description", &p.Description)
+		text("tag", &p.Tag)
+		text("tag_color", &p.TagColor)
+		text("image_url_1", &p.ImageURL1)
+		text("image_url_2", &p.ImageURL2)
+		text("image_url_3", &p.ImageURL3)
+
+		integer := func(name string, min, fallback int, dest *int) {
+			*dest = fallback
+			value, ok := cell(name)
+			if !ok {
+				return
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n < min {
+				fail("%s must be a whole number of at least %d", name, min)
+				return
+			}
+			*dest = n
+		}
+		integer("stock", 0, 100, &p.Stock)
+		integer("low_stock_threshold", 0, 5, &p.LowStockThreshold)
+		integer("min_order_tb", 1, 1, &p.MinOrderTB)
+		integer("min_order_luar_tb", 1, 1, &p.MinOrderLuarTB)
+		p.MinOrder = p.MinOrderTB
+
+		p.IsAvailable = true
+		if value, ok := cell("is_available"); ok {
+			available, err := parseBool(value)
+			if err != nil {
+				fail("is_available: %v", err)
+			}
+			p.IsAvailable = available
+		}
+
+		var err error
+		value, _ := cell("available_days_tb")
+		if p.AvailableDaysTB, err = parseDays(value); err != nil {
+			fail("available_days_tb: %v", err)
+		}
+		value, _ = cell("available_days_luar_tb")
+		if p.AvailableDaysLuarTB, err = parseDays(value); err != nil {
+			fail("available_days_luar_tb: %v", err)
+		}
+		value, _ = cell("conditions")
+		if p.Conditions, err = parseConditions(value); err != nil {
+			fail("conditions: %v", err)
+		}
+		value, _ = cell("addons")
+		if p.Addons, err = parseAddons(value); err != nil {
+			fail("addons: %v", err)
+		}
+		value, _ = cell("variants")
+		if row.variants, err = parseVariants(value); err != nil {
+			fail("variants: %v", err)
+		}
+
+		report.Total++
+		switch {
+		case len(row.Errors) > 0:
+			report.Failed++
+		case row.Action == "update":
+			report.Updated++
+		default:
+			report.Created++
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	return report, ""
+}
+
+// Create or update the product of a validated row
+func applyProductImportRow(row productImportRow, hasColumn func(string) bool, actor string) error {
+	p := row.product
+
+	if row.Action == "create" {
+		err := db.DB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&p).Error; err != nil {
+				return err
+			}
+			// Zero values are skipped on create for columns with a default
+			return tx.Model(&p).Updates(map[string]interface{}{
+				"is_available":        p.IsAvailable,
+				"low_stock_threshold": p.LowStockThreshold,
+			}).Error
+		})
+		if err != nil {
+			return err
+		}
+		recordOpeningStock(p.ID, nil, p.Stock, actor)
+		if len(row.variants) > 0 {
+			syncProductVariants(p, row.variants, actor)
+		}
+		return nil
+	}
+
+	var current models.Product
+	if err := db.DB.Preload("Variants").First(&current, "id = ?", row.existingID).Error; err != nil {
+		return err
+	}
+
+	// Columns left out of the file, and empty stock settings, keep their value
+	updates := map[string]interface{}{
+		"name":      p.Name,
+		"price":     p.Price,
+		"min_order": p.MinOrder,
+	}
+	columns := map[string]interface{}{
+		"category":               p.Category,
+		"short_description":      p.ShortDescription,
+		"description":            p.Description,
+		"tag":                    p.Tag,
+		"tag_color":              p.TagColor,
+		"image_url_1":            p.ImageURL1,
+		"image_url_2":            p.ImageURL2,
+		"image_url_3":            p.ImageURL3,
+		"min_order_tb":           p.MinOrderTB,
+		"min_order_luar_tb":      p.MinOrderLuarTB,
+		"available_days_tb":      p.AvailableDaysTB,
+		"available_days_luar_tb": p.AvailableDaysLuarTB,
+		"conditions":             p.Conditions,
+		"addons":                 p.Addons,
+	}
+	for name, value := range columns {
+		if hasColumn(name) {
+			updates[name] = value
+		}
+	}
+	if !row.blank["low_stock_threshold"] {
+		updates["low_stock_threshold"] = p.LowStockThreshold
+	}
+	if !row.blank["is_available"] {
+		updates["is_available"] = p.IsAvailable
+	}
+	if err := db.DB.Model(&current).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	if !row.blank["stock"] && p.Stock != current.Stock {
+		if _, err := recordStockMovement(models.InventoryMovement{
+			ProductID: current.ID,
+			Type:      MovementAdjust,
+			Quantity:  p.Stock - current.Stock,
+			Reason:    "Product import",
+			Actor:     actor,
+		}); err != nil {
+			return err
+		}
+	}
+
+	if hasColumn("variants") {
+		// The sheet has no variant availability, keep what admins set
+		available := make(map[string]bool)
+		for _, v := range current.Variants {
+			available[v.Name] = v.IsAvailable
+		}
+		for i, v := range row.variants {
+			if isAvailable, ok := available[v.Name]; ok {
+				row.variants[i].IsAvailable = isAvailable
+			}
+		}
+		syncProductVariants(current, row.variants, actor)
+	}
+	return nil
+}
+
+func registerProductImportRoutes(app *fiber.App) {
+	// Admin: Import products from CSV or XLSX (multipart "file", ?mode=create|upsert&dry_run=true)
+	app.Post("/api/admin/products/import", func(c *fiber.Ctx) error {
+		mode := c.Query("mode", c.FormValue("mode", ImportModeCreate))
+		if mode != ImportModeCreate && mode != ImportModeUpsert {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Mode must be create or upsert",
+			})
+		}
+		dryRun, _ := strconv.ParseBool(c.Query("dry_run", c.FormValue("dry_run", "false")))
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Upload the products as a CSV or XLSX file in the \"file\" field",
+			})
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to read the file",
+			})
+		}
+		data, err := io.ReadAll(file)
+		file.Close()
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to read the file",
+			})
+		}
+
+		rows, err := readProductSheet(data)
+		if err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": fmt.Sprintf("Failed to read the file: %v", err),
+			})
+		}
+		report, msg := buildProductImport(rows, mode)
+		if msg != "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": msg,
+			})
+		}
+		report.DryRun = dryRun
+
+		if dryRun {
+			return c.JSON(fiber.Map{
+				"success": report.Failed == 0,
+				"data":    report,
+				"message": fmt.Sprintf("%d to create, %d to update, %d with errors", report.Created, report.Updated, report.Failed),
+			})
+		}
+		// All or nothing, fix the file and import again
+		if report.Failed > 0 {
+			return c.Status(422).JSON(fiber.Map{
+				"success": false,
+				"data":    report,
+				"message": fmt.Sprintf("%d rows have errors, nothing was imported", report.Failed),
+			})
+		}
+
+		columns := productSheetHeader(rows[0])
+		hasColumn := func(name string) bool {
+			_, ok := columns[name]
+			return ok
+		}
+		actor := auth.Current(c).Email
+		report.Created, report.Updated = 0, 0
+		for i, row := range report.Rows {
+			if err := applyProductImportRow(row, hasColumn, actor); err != nil {
+				log.Printf("❌ Product import row %d (%s) failed: %v", row.Row, row.Name, err)
+				report.Rows[i].Errors = append(report.Rows[i].Errors, "failed to save the product")
+				report.Failed++
+				continue
+			}
+			if row.Action == "update" {
+				report.Updated++
+			} else {
+				report.Created++
+			}
+		}
+
+		log.Printf("📥 Product import by %s: %d created, %d updated, %d failed", actor, report.Created, report.Updated, report.Failed)
+		return c.JSON(fiber.Map{
+			"success": report.Failed == 0,
+			"data":    report,
+			"message": fmt.Sprintf("%d created, %d updated, %d failed", report.Created, report.Updated, report.Failed),
+		})
+	})
+
+	// Admin: Export products in the import format (?format=csv|xlsx)
+	app.Get("/api/admin/products/export", func(c *fiber.Ctx) error {
+		// Option lists as "name=value; ...", only when the JSON is an array
+		optionList := func(column, valueKey string) string {
+			return fmt.Sprintf(`COALESCE((SELECT string_agg((o->>'name') || '=' || COALESCE(o->>'%[2]s', '0'), '; ')
+				FROM jsonb_array_elements(CASE WHEN jsonb_typeof(p.%[1]s) = 'array' THEN p.%[1]s ELSE '[]'::jsonb END) o), '')`, column, valueKey)
+		}
+
+		sheet := exportSheet{
+			Key:     "products",
+			Name:    "Produk",
+			Headers: productSheetColumns,
+			Query: func() *gorm.DB {
+				return db.DB.Table("products p").
+					Select(`COALESCE(p.sku, ''), p.name, COALESCE(p.category, ''), p.price::float8,
+						COALESCE(p.short_description, ''), COALESCE(p.description, ''),
+						COALESCE(p.tag, ''), COALESCE(p.tag_color, ''),
+						COALESCE(p.image_url_1, ''), COALESCE(p.image_url_2, ''), COALESCE(p.image_url_3, ''),
+						p.stock, p.low_stock_threshold, p.is_available,
+						COALESCE(p.min_order_tb, 1), COALESCE(p.min_order_luar_tb, 1),
+						COALESCE(array_to_string(p.available_days_tb, ','), ''),
+						COALESCE(array_to_string(p.available_days_luar_tb, ','), ''),
+						` + optionList("conditions", "price_adjustment") + `,
+						` + optionList("addons", "price") + `,
+						COALESCE((SELECT string_agg(v.name || '=' || v.price::float8 || ':' || v.stock, '; ' ORDER BY v.created_at)
+							FROM product_variants v WHERE v.product_id = p.id), '')`).
+					Order("p.category ASC, p.name ASC")
+			},
+		}
+		return streamExport(c, "produk", []exportSheet{sheet})
+	})
+}
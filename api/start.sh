@@ -40,4 +40,4 @@ echo "━━━━━━━━━━━━━━━━━━━━━━━━
 echo ""
 
 # Run the server
-go run main.go
+go run .
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"log"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/email"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/validate"
+	"scaff-food-backend/internal/webhook"
+
+	"gorm.io/gorm"
+)
+
+// ==================== STOCK LEVELS ====================
+
+// Stock alert levels
+const (
+	StockLevelLow     = "low"
+	StockLevelSoldOut = "sold_out"
+)
+
+// stockChange is the stock of a product, or one of its variants, before
+// and after a change
+type stockChange struct {
+	ProductID   string
+	VariantID   string
+	ProductName string
+	VariantName string
+	Before      int `gorm:"column:stock_before"`
+	After       int `gorm:"column:stock_after"`
+	Threshold   int
+}
+
+// Change the stock of a product, or of its variant when variantID is set,
+// by delta. Stock never goes below zero. Returns nil if nothing matched.
+func adjustStock(tx *gorm.DB, productID string, variantID *string, delta int) (*stockChange, error) {
+	var changes []stockChange
+	var err error
+
+	if variantID != nil && *variantID != "" {
+		err = tx.Raw(`
+			WITH old AS (
+				SELECT id, stock FROM product_variants WHERE id = ? AND product_id = ? FOR UPDATE
+			)
+			UPDATE product_variants v SET stock = GREATEST(old.stock + ?, 0), updated_at = NOW()
+			FROM old, products p
+			WHERE v.id = old.id AND p.id = v.product_id
+			RETURNING v.product_id, v.id AS variant_id, p.name AS product_name, v.name AS variant_name,
+				old.stock AS stock_before, v.stock AS stock_after, p.low_stock_threshold AS threshold
+		`, *variantID, productID, delta).Scan(&changes).Error
+	} else {
+		err = tx.Raw(`
+			WITH old AS (
+				SELECT id, stock FROM products WHERE id = ? FOR UPDATE
+			)
+			UPDATE products p SET stock = GREATEST(old.stock + ?, 0), updated_at = NOW()
+			FROM old
+			WHERE p.id = old.id
+			RETURNING p.id AS product_id, '' AS variant_id, p.name AS product_name, '' AS variant_name,
+				old.stock AS stock_before, p.stock AS stock_after, p.low_stock_threshold AS threshold
+		`, productID, delta).Scan(&changes).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	return &changes[0], nil
+}
+
+// Hide sold out stock, bring back restocked stock that was hidden
+// automatically and alert admins when stock crosses the threshold
+func reactToStockChange(change stockChange) {
+	if !db.Ready() || change.Before == change.After {
+		return
+	}
+
+	table, id := "products", change.ProductID
+	if change.VariantID != "" {
+		table, id = "product_variants", change.VariantID
+	}
+
+	switch {
+	case change.After == 0:
+		db.DB.Table(table).Where("id = ? AND is_available = ?", id, true).Updates(map[string]interface{}{
+			"is_available":  false,
+			"auto_disabled": true,
+		})
+		sendStockAlert(change, StockLevelSoldOut)
+	case change.Before == 0:
+		// Products an admin switched off by hand stay off
+		result := db.DB.Table(table).Where("id = ? AND auto_disabled = ?", id, true).Updates(map[string]interface{}{
+			"is_available":  true,
+			"auto_disabled": false,
+		})
+		if result.RowsAffected > 0 {
+			log.Printf("✅ %s restocked to %d, available again", stockLabel(change), change.After)
+		}
+	}
+
+	if change.After > 0 && change.After <= change.Threshold && change.Before > change.Threshold {
+		sendStockAlert(change, StockLevelLow)
+	}
+}
+
+// Take order items out of stock (sale) or put them back (cancel_return)
+func applyOrderStock(order models.Order, items []models.OrderItem, movementType, actor string) {
+	if !db.Ready() {
+		return
+	}
+	sign := -1
+	if movementType == MovementCancelReturn {
+		sign = 1
+	}
+	orderID := order.ID
+	for _, item := range items {
+		if item.ProductID == "" || item.Quantity <= 0 {
+			continue
+		}
+		change, err := recordStockMovement(models.InventoryMovement{
+			ProductID: item.ProductID,
+			VariantID: item.VariantID,
+			Type:      movementType,
+			Quantity:  sign * item.Quantity,
+			Reason:    order.OrderNumber,
+			Actor:     actor,
+			OrderID:   &orderID,
+		})
+		if err != nil {
+			log.Printf("❌ Failed to update stock for %s: %v", item.ProductName, err)
+			continue
+		}
+		if change == nil {
+			log.Printf("⚠️ No stock to update for %s (product %s)", item.ProductName, item.ProductID)
+		}
+	}
+}
+
+// Product name with its variant, for logs and alerts
+func stockLabel(change stockChange) string {
+	if change.VariantName != "" {
+		return fmt.Sprintf("%s (%s)", change.ProductName, change.VariantName)
+	}
+	return change.ProductName
+}
+
+// Admin addresses for stock alerts, STOCK_ALERT_EMAILS or every admin user
+func stockAlertRecipients() []string {
+	var recipients []string
+	for _, addr := range appConfig.StockAlertEmails {
+		if addr = validate.NormalizeEmail(addr); addr != "" {
+			recipients = append(recipients, addr)
+		}
+	}
+	if len(recipients) == 0 && db.Ready() {
+		db.DB.Model(&models.User{}).Where("role NOT IN ?", []string{auth.RoleCustomer, auth.RoleCourier}).Pluck("email", &recipients)
+	}
+	return recipients
+}
+
+// Alert admins by email and webhook, failures are only logged
+func sendStockAlert(change stockChange, level string) {
+	label := stockLabel(change)
+	log.Printf("📉 Stock %s: %s has %d left", level, label, change.After)
+
+	summary := fmt.Sprintf("📉 Stok menipis: %s tinggal %d", label, change.After)
+	if level == StockLevelSoldOut {
+		summary = fmt.Sprintf("🚫 Stok habis: %s disembunyikan dari menu", label)
+	}
+	dispatchWebhooks(webhook.EventProductStockLow, map[string]interface{}{
+		"product_id":   change.ProductID,
+		"variant_id":   change.VariantID,
+		"product_name": change.ProductName,
+		"variant_name": change.VariantName,
+		"stock":        change.After,
+		"threshold":    change.Threshold,
+		"level":        level,
+	}, summary)
+
+	if !getSMTPConfig().Configured() {
+		return
+	}
+
+	rendered, err := email.Render(email.TemplateStockAlert, email.StockAlertData{
+		ProductName: change.ProductName,
+		VariantName: change.VariantName,
+		Stock:       change.After,
+		Threshold:   change.Threshold,
+		SoldOut:     level == StockLevelSoldOut,
+	})
+	if err != nil {
+		log.Printf("❌ Failed to render stock alert for %s: %v", label, err)
+		return
+	}
+	for _, to := range stockAlertRecipients() {
+		if err := queueEmail(to, rendered); err != nil {
+			log.Printf("❌ Failed to queue stock alert to %s: %v", to, err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/escpos"
+	"scaff-food-backend/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ==================== KITCHEN TICKETS ====================
+
+// Add the kitchen ticket of one order to the receipt, ending with a cut
+func writeKitchenTicket(r *escpos.Receipt, order models.Order, items []models.OrderItem) {
+	r.Title(order.OrderNumber)
+	if order.DeliveryDate != nil {
+		r.Center("Kirim " + order.DeliveryDate.Format("02-01-2006"))
+	}
+	r.Center("Lokasi " + order.DeliveryLocation)
+	r.Center(order.CustomerName)
+	r.Rule()
+
+	for _, item := range items {
+		r.Bold(fmt.Sprintf("%dx %s", item.Quantity, item.ProductName))
+		for _, option := range orderItemOptions(item) {
+			r.Indent(3, "- "+option)
+		}
+	}
+
+	if order.Notes != "" {
+		r.Rule()
+		r.Bold("CATATAN:")
+		r.Text(order.Notes)
+	}
+
+	r.Rule()
+	r.Columns("Pesan "+order.CreatedAt.Format("02-01 15:04"), "Cetak "+time.Now().Format("15:04"))
+	r.Feed(1)
+	r.Cut()
+}
+
+// Paper width from ?paper=58|80, in characters
+func ticketWidth(c *fiber.Ctx) (int, bool) {
+	switch c.Query("paper", "58") {
+	case "58":
+		return escpos.Width58mm, true
+	case "80":
+		return escpos.Width80mm, true
+	default:
+		return 0, false
+	}
+}
+
+// Send the receipt as ESC/POS bytes or plain text
+func sendTicket(c *fiber.Ctx, r *escpos.Receipt, filename string) error {
+	switch c.Query("format", "escpos") {
+	case "escpos":
+		c.Set(fiber.HeaderContentType, "application/octet-stream")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s.bin"`, filename))
+		return c.Send(r.ESCPOS())
+	case "text":
+		c.Set(fiber.HeaderContentType, "text/plain; charset=utf-8")
+		return c.SendString(r.PlainText())
+	default:
+		return c.Status(400).JSON(fiber.Map{
+			"success": false,
+			"message": "Format must be escpos or text",
+		})
+	}
+}
+
+func registerTicketRoutes(app *fiber.App) {
+	// Admin: Kitchen ticket of an order (?format=escpos|text&paper=58|80)
+	app.Get("/api/admin/orders/:id/ticket", func(c *fiber.Ctx) error {
+		width, ok := ticketWidth(c)
+		if !ok {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Paper must be 58 or 80",
+			})
+		}
+
+		var order models.Order
+		if err := db.DB.First(&order, "id = ?", c.Params("id")).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Order not found",
+			})
+		}
+		var items []models.OrderItem
+		db.DB.Where("order_id = ?", order.ID).Order("created_at ASC").Find(&items)
+
+		receipt := escpos.New(width)
+		writeKitchenTicket(receipt, order, items)
+		return sendTicket(c, receipt, "tiket-"+order.OrderNumber)
+	})
+
+	// Admin: Kitchen tickets of every active order for a delivery date
+	app.Get("/api/admin/production/tickets", func(c *fiber.Ctx) error {
+		date := c.Query("date", time.Now().Format("2006-01-02"))
+		if _, err := time.Parse("2006-01-02", date); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Date must be in YYYY-MM-DD format",
+			})
+		}
+		width, ok := ticketWidth(c)
+		if !ok {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Paper must be 58 or 80",
+			})
+		}
+
+		var orders []models.Order
+		if err := db.DB.Where("order_status IN ? AND delivery_date = ?", models.ActiveOrderStatuses, date).
+			Order("delivery_location ASC, created_at ASC").
+			Find(&orders).Error; err != nil {
+			log.Printf("Error fetching orders for tickets: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to fetch orders",
+			})
+		}
+		if len(orders) == 0 {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "No active orders for this date",
+			})
+		}
+
+		ids := make([]string, len(orders))
+		for i, order := range orders {
+			ids[i] = order.ID
+		}
+		var items []models.OrderItem
+		db.DB.Where("order_id IN ?", ids).Order("created_at ASC").Find(&items)
+		byOrder := make(map[string][]models.OrderItem)
+		for _, item := range items {
+			byOrder[item.OrderID] = append(byOrder[item.OrderID], item)
+		}
+
+		receipt := escpos.New(width)
+		for _, order := range orders {
+			writeKitchenTicket(receipt, order, byOrder[order.ID])
+		}
+		return sendTicket(c, receipt, "tiket-"+date)
+	})
+}
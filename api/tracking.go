@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/realtime"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ==================== PUBLIC ORDER TRACKING ====================
+
+// TrackingItem is an order item without prices or product references
+type TrackingItem struct {
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+}
+
+// TrackingView is the redacted order shown to anyone holding the tracking link
+type TrackingView struct {
+	OrderNumber      string     `json:"order_number"`
+	CustomerName     string     `json:"customer_name"`
+	DeliveryLocation string     `json:"delivery_location"`
+	DeliveryDate     *time.Time `json:"delivery_date,omitempty"`
+	Total            float64    `json:"total"`
+	PaymentStatus    string     `json:"payment_status"`
+	OrderStatus      string     `json:"order_status"`
+	// Set once the order is delivered or cancelled
+	DeliveryPhoto       string         `json:"delivery_photo,omitempty"`
+	AppreciationMessage string         `json:"appreciation_message,omitempty"`
+	CancellationReason  string         `json:"cancellation_reason,omitempty"`
+	Items               []TrackingItem `json:"items"`
+	CreatedAt           time.Time      `json:"created_at"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+}
+
+// Mask a customer name down to the first letter of each word
+func maskName(name string) string {
+	masked := []rune{}
+	start := true
+	for _, r := range name {
+		switch {
+		case r == ' ':
+			masked = append(masked, r)
+			start = true
+		case start:
+			masked = append(masked, r)
+			start = false
+		default:
+			masked = append(masked, '*')
+		}
+	}
+	return string(masked)
+}
+
+// Build the redacted tracking view of an order
+func newTrackingView(order models.Order, items []models.OrderItem) TrackingView {
+	view := TrackingView{
+		OrderNumber:         order.OrderNumber,
+		CustomerName:        maskName(order.CustomerName),
+		DeliveryLocation:    order.DeliveryLocation,
+		DeliveryDate:        order.DeliveryDate,
+		Total:               order.Total,
+		PaymentStatus:       order.PaymentStatus,
+		OrderStatus:         order.OrderStatus,
+		DeliveryPhoto:       order.DeliveryPhoto,
+		AppreciationMessage: order.AppreciationMessage,
+		CancellationReason:  order.CancellationReason,
+		Items:               []TrackingItem{},
+		CreatedAt:           order.CreatedAt,
+		UpdatedAt:           order.UpdatedAt,
+	}
+	for _, item := range items {
+		view.Items = append(view.Items, TrackingItem{
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+		})
+	}
+	return view
+}
+
+// Public link to the tracking view of an order
+func trackingURL(token string) string {
+	return strings.TrimRight(appConfig.PublicURL, "/") + "/api/track/" + token
+}
+
+// Find an order by its tracking token
+func findTrackedOrder(token string) (*models.Order, bool) {
+	if len(token) != 64 {
+		return nil, false
+	}
+	var order models.Order
+	if err := db.DB.First(&order, "tracking_token = ?", token).Error; err != nil {
+		return nil, false
+	}
+	return &order, true
+}
+
+// Load the current tracking view of an order
+func loadTrackingView(orderID string) (TrackingView, error) {
+	var order models.Order
+	if err := db.DB.First(&order, "id = ?", orderID).Error; err != nil {
+		return TrackingView{}, err
+	}
+	var items []models.OrderItem
+	db.DB.Where("order_id = ?", order.ID).Find(&items)
+	return newTrackingView(order, items), nil
+}
+
+// Tracking event carrying the current view of the order
+func trackingEvent(event realtime.Event) (realtime.Event, bool) {
+	view, err := loadTrackingView(event.OrderID)
+	if err != nil {
+		return event, false
+	}
+	data, err := json.Marshal(view)
+	if err != nil {
+		return event, false
+	}
+	event.Data = string(data)
+	return event, true
+}
+
+func registerTrackingRoutes(app *fiber.App) {
+	// Get redacted order status by tracking token (public)
+	app.Get("/api/track/:token", func(c *fiber.Ctx) error {
+		order, ok := findTrackedOrder(c.Params("token"))
+		if !ok {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Order not found",
+			})
+		}
+
+		view, err := loadTrackingView(order.ID)
+		if err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Order not found",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    view,
+		})
+	})
+
+	// Live order status by tracking token (public, SSE)
+	app.Get("/api/track/:token/stream", func(c *fiber.Ctx) error {
+		order, ok := findTrackedOrder(c.Params("token"))
+		if !ok {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Order not found",
+			})
+		}
+
+		// Current state first, so reconnecting clients need no replay
+		latestID, _ := realtime.LatestID(db.DB)
+		snapshot, ok := trackingEvent(realtime.Event{
+			ID:      latestID,
+			Type:    realtime.EventOrderSnapshot,
+			OrderID: order.ID,
+		})
+		if !ok {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Order not found",
+			})
+		}
+
+		orderID := order.ID
+		return streamOrderEvents(c, orderStream{
+			Filter: func(event realtime.Event) bool {
+				return event.OrderID == orderID && event.Type != realtime.EventOrderCreated
+			},
+			LastID:    -1,
+			Snapshot:  &snapshot,
+			Transform: trackingEvent,
+		})
+	})
+}
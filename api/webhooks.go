@@ -0,0 +1,384 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"scaff-food-backend/internal/auth"
+	"scaff-food-backend/internal/db"
+	"scaff-food-backend/internal/models"
+	"scaff-food-backend/internal/outbox"
+	"scaff-food-backend/internal/realtime"
+	"scaff-food-backend/internal/webhook"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+)
+
+// ==================== WEBHOOKS ====================
+
+// Outbox channel for webhook deliveries, the recipient is the webhook id
+const OutboxChannelWebhook = "webhook"
+
+var webhookSender = webhook.NewSender(10 * time.Second)
+
+// WebhookRequest is the body of create and update
+type WebhookRequest struct {
+	Name     string   `json:"name"`
+	URL      string   `json:"url"`
+	Events   []string `json:"events"`
+	Format   string   `json:"format"`
+	IsActive *bool    `json:"is_active"`
+	// Update only: issue a new signing secret
+	RotateSecret bool `json:"rotate_secret"`
+}
+
+// Outbox payload of one webhook delivery, the body is built when the
+// event happens so retries send the same content
+type webhookJob struct {
+	WebhookID string          `json:"webhook_id"`
+	Event     string          `json:"event"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// Validate and clean a webhook request
+func validateWebhookRequest(ctx context.Context, req *WebhookRequest) string {
+	req.Name = strings.TrimSpace(req.Name)
+	req.URL = strings.TrimSpace(req.URL)
+	if req.Name == "" {
+		return "Name is required"
+	}
+	if err := webhook.ValidateURL(ctx, req.URL); err != nil {
+		return err.Error()
+	}
+	if len(req.Events) == 0 {
+		return "At least one event is required"
+	}
+	for _, e := range req.Events {
+		if !webhook.IsEventType(e) {
+			return "Unknown event: " + e
+		}
+	}
+	if req.Format == "" {
+		req.Format = webhook.FormatJSON
+	}
+	if req.Format != webhook.FormatJSON && req.Format != webhook.FormatDiscord {
+		return "Format must be json or discord"
+	}
+	return ""
+}
+
+// Deliver queued webhook jobs, a deleted or disabled webhook drops them
+func handleWebhookDelivery(ctx context.Context, msg outbox.Message) error {
+	var job webhookJob
+	if err := json.Unmarshal([]byte(msg.Payload), &job); err != nil {
+		return err
+	}
+
+	if !db.Ready() {
+		return outbox.ErrNoDatabase
+	}
+
+	var hook webhook.Webhook
+	if err := db.DB.First(&hook, "id = ?", job.WebhookID).Error; err != nil {
+		log.Printf("⚠️ Dropping webhook delivery %s: webhook %s not found", msg.ID, job.WebhookID)
+		return nil
+	}
+	if !hook.IsActive {
+		log.Printf("⚠️ Dropping webhook delivery %s: webhook %s disabled", msg.ID, hook.Name)
+		return nil
+	}
+
+	return webhookSender.Send(ctx, hook.Secret, webhook.Delivery{
+		ID:    msg.ID,
+		Event: job.Event,
+		URL:   hook.URL,
+		Body:  job.Body,
+	})
+}
+
+// Queue an event for every active webhook subscribed to it,
+// failures are only logged
+func dispatchWebhooks(eventType string, data interface{}, summary string) {
+	if !db.Ready() {
+		return
+	}
+
+	var hooks []webhook.Webhook
+	if err := db.DB.Where("is_active = ? AND ? = ANY(events)", true, eventType).Find(&hooks).Error; err != nil {
+		log.Printf("❌ Failed to load webhooks for %s: %v", eventType, err)
+		return
+	}
+
+	envelope := webhook.Envelope{
+		Event:     eventType,
+		CreatedAt: time.Now(),
+		Data:      data,
+	}
+	for _, hook := range hooks {
+		body, err := webhook.Body(hook.Format, envelope, summary)
+		if err != nil {
+			log.Printf("❌ Failed to encode %s for webhook %s: %v", eventType, hook.Name, err)
+			continue
+		}
+		job := webhookJob{WebhookID: hook.ID, Event: eventType, Body: body}
+		if _, err := outboxQueue.Enqueue(OutboxChannelWebhook, hook.ID, job); err != nil {
+			log.Printf("❌ Failed to queue %s for webhook %s: %v", eventType, hook.Name, err)
+		}
+	}
+}
+
+// One line description of an order event for chat webhooks
+func orderWebhookSummary(eventType string, order models.Order) string {
+	switch eventType {
+	case realtime.EventOrderCreated:
+		return fmt.Sprintf("🧾 Pesanan baru %s dari %s, total %s", order.OrderNumber, order.CustomerName, formatRupiah(order.Total))
+	case realtime.EventOrderStatusChanged:
+		return fmt.Sprintf("📦 Pesanan %s sekarang %s", order.OrderNumber, order.OrderStatus)
+	case realtime.EventPaymentUpdated:
+		return fmt.Sprintf("💰 Pembayaran pesanan %s: %s", order.OrderNumber, order.PaymentStatus)
+	}
+	return fmt.Sprintf("%s %s", eventType, order.OrderNumber)
+}
+
+func registerWebhookRoutes(app *fiber.App) {
+	// Admin: List event types webhooks can subscribe to
+	app.Get("/api/admin/webhooks/events", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    webhook.EventTypes,
+		})
+	})
+
+	// Admin: List webhooks
+	app.Get("/api/admin/webhooks", func(c *fiber.Ctx) error {
+		var hooks []webhook.Webhook
+		if err := db.DB.Order("created_at DESC").Find(&hooks).Error; err != nil {
+			log.Printf("Error fetching webhooks: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to fetch webhooks",
+			})
+		}
+
+		// The secret is only shown when created or rotated
+		for i := range hooks {
+			hooks[i].Secret = ""
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    hooks,
+		})
+	})
+
+	// Admin: Register a webhook
+	app.Post("/api/admin/webhooks", func(c *fiber.Ctx) error {
+		var req WebhookRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+		if msg := validateWebhookRequest(c.UserContext(), &req); msg != "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": msg,
+			})
+		}
+
+		hook := webhook.Webhook{
+			Name:     req.Name,
+			URL:      req.URL,
+			Secret:   "whsec_" + auth.GenerateToken(24),
+			Events:   pq.StringArray(req.Events),
+			Format:   req.Format,
+			IsActive: req.IsActive == nil || *req.IsActive,
+		}
+		if err := db.DB.Create(&hook).Error; err != nil {
+			log.Printf("Error creating webhook: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to create webhook",
+			})
+		}
+		// GORM skips false on create because of the column default
+		if !hook.IsActive {
+			db.DB.Model(&hook).Update("is_active", false)
+		}
+
+		log.Printf("🪝 Webhook %s registered for %s", hook.Name, strings.Join(hook.Events, ", "))
+
+		return c.Status(201).JSON(fiber.Map{
+			"success": true,
+			"data":    hook,
+			"message": "Webhook created, store the secret now, it is not shown again",
+		})
+	})
+
+	// Admin: Update a webhook
+	app.Put("/api/admin/webhooks/:id", func(c *fiber.Ctx) error {
+		var hook webhook.Webhook
+		if err := db.DB.First(&hook, "id = ?", c.Params("id")).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Webhook not found",
+			})
+		}
+
+		var req WebhookRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": "Invalid request body",
+			})
+		}
+		if msg := validateWebhookRequest(c.UserContext(), &req); msg != "" {
+			return c.Status(400).JSON(fiber.Map{
+				"success": false,
+				"message": msg,
+			})
+		}
+
+		updates := map[string]interface{}{
+			"name":       req.Name,
+			"url":        req.URL,
+			"events":     pq.StringArray(req.Events),
+			"format":     req.Format,
+			"updated_at": time.Now(),
+		}
+		if req.IsActive != nil {
+			updates["is_active"] = *req.IsActive
+		}
+		if req.RotateSecret {
+			updates["secret"] = "whsec_" + auth.GenerateToken(24)
+		}
+
+		if err := db.DB.Model(&hook).Updates(updates).Error; err != nil {
+			log.Printf("Error updating webhook: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to update webhook",
+			})
+		}
+		db.DB.First(&hook, "id = ?", hook.ID)
+		if !req.RotateSecret {
+			hook.Secret = ""
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    hook,
+			"message": "Webhook updated",
+		})
+	})
+
+	// Admin: Delete a webhook
+	app.Delete("/api/admin/webhooks/:id", func(c *fiber.Ctx) error {
+		result := db.DB.Delete(&webhook.Webhook{}, "id = ?", c.Params("id"))
+		if result.Error != nil {
+			log.Printf("Error deleting webhook: %v", result.Error)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to delete webhook",
+			})
+		}
+		if result.RowsAffected == 0 {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Webhook not found",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Webhook deleted",
+		})
+	})
+
+	// Admin: Delivery log of a webhook (?limit=50), failed ones can be
+	// retried through /api/admin/outbox/:id/retry
+	app.Get("/api/admin/webhooks/:id/deliveries", func(c *fiber.Ctx) error {
+		var hook webhook.Webhook
+		if err := db.DB.First(&hook, "id = ?", c.Params("id")).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Webhook not found",
+			})
+		}
+
+		limit := c.QueryInt("limit", 50)
+		if limit < 1 || limit > 500 {
+			limit = 50
+		}
+
+		deliveries, err := outboxQueue.ListFor(OutboxChannelWebhook, hook.ID, limit)
+		if err != nil {
+			log.Printf("Error fetching webhook deliveries: %v", err)
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to fetch deliveries",
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"data":    deliveries,
+		})
+	})
+
+	// Admin: Send a test event right away and report the result
+	app.Post("/api/admin/webhooks/:id/test", func(c *fiber.Ctx) error {
+		var hook webhook.Webhook
+		if err := db.DB.First(&hook, "id = ?", c.Params("id")).Error; err != nil {
+			return c.Status(404).JSON(fiber.Map{
+				"success": false,
+				"message": "Webhook not found",
+			})
+		}
+
+		body, err := webhook.Body(hook.Format, webhook.Envelope{
+			Event:     webhook.EventTest,
+			CreatedAt: time.Now(),
+			Data:      fiber.Map{"webhook_id": hook.ID, "name": hook.Name},
+		}, "✅ Tes webhook SCAFF*FOOD berhasil: "+hook.Name)
+		if err != nil {
+			return c.Status(500).JSON(fiber.Map{
+				"success": false,
+				"message": "Failed to build test event",
+			})
+		}
+
+		err = webhookSender.Send(c.Context(), hook.Secret, webhook.Delivery{
+			ID:    "test-" + auth.GenerateToken(8),
+			Event: webhook.EventTest,
+			URL:   hook.URL,
+			Body:  body,
+		})
+		if err != nil {
+			// Only the status code is reported, never what the endpoint sent
+			message := "Test delivery failed: endpoint unreachable"
+			var statusErr *webhook.StatusError
+			if errors.As(err, &statusErr) {
+				message = fmt.Sprintf("Test delivery failed: endpoint responded %d", statusErr.StatusCode)
+			} else if errors.Is(err, webhook.ErrBlockedAddress) {
+				message = "Test delivery failed: " + webhook.ErrBlockedAddress.Error()
+			}
+			return c.Status(502).JSON(fiber.Map{
+				"success": false,
+				"message": message,
+			})
+		}
+
+		return c.JSON(fiber.Map{
+			"success": true,
+			"message": "Test event delivered",
+		})
+	})
+}
@@ -1,4 +1,5 @@
 import { NextRequest, NextResponse } from 'next/server';
+import { forwardedAuth } from '@/lib/auth-headers';
 
 // Catch-all API proxy - forwards all /api/* requests to backend
 
@@ -66,6 +67,7 @@ async function proxyRequest(
       method,
       headers: {
         'Content-Type': 'application/json',
+        ...forwardedAuth(request),
       },
     };
     
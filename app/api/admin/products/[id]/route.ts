@@ -1,4 +1,5 @@
 import { NextRequest, NextResponse } from 'next/server';
+import { forwardedAuth } from '@/lib/auth-headers';
 
 export async function PUT(
   request: NextRequest,
@@ -16,6 +17,7 @@ export async function PUT(
       method: 'PUT',
       headers: {
         'Content-Type': 'application/json',
+        ...forwardedAuth(request),
       },
       body: JSON.stringify(body),
     });
@@ -48,6 +50,7 @@ export async function DELETE(
       method: 'DELETE',
       headers: {
         'Content-Type': 'application/json',
+        ...forwardedAuth(request),
       },
     });
 
@@ -77,6 +80,7 @@ export async function PATCH(
       method: 'PATCH',
       headers: {
         'Content-Type': 'application/json',
+        ...forwardedAuth(request),
       },
     });
 
@@ -1,4 +1,5 @@
 import { NextRequest, NextResponse } from 'next/server';
+import { forwardedAuth } from '@/lib/auth-headers';
 
 export async function PATCH(
   request: NextRequest,
@@ -15,6 +16,7 @@ export async function PATCH(
       method: 'PATCH',
       headers: {
         'Content-Type': 'application/json',
+        ...forwardedAuth(request),
       },
     });
 
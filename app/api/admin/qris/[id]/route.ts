@@ -1,4 +1,5 @@
 import { NextRequest, NextResponse } from 'next/server';
+import { forwardedAuth } from '@/lib/auth-headers';
 
 export async function PUT(
   request: NextRequest,
@@ -15,6 +16,7 @@ export async function PUT(
       method: 'PUT',
       headers: {
         'Content-Type': 'application/json',
+        ...forwardedAuth(request),
       },
       body: JSON.stringify(body),
     });
@@ -44,6 +46,7 @@ export async function DELETE(
       method: 'DELETE',
       headers: {
         'Content-Type': 'application/json',
+        ...forwardedAuth(request),
       },
     });
 
@@ -5,6 +5,7 @@ import { motion, AnimatePresence } from 'framer-motion';
 import { Spinner } from '../../../components/ui/ios-spinner';
 import '../dashboard-new.css';
 import './event.css';
+import { authHeaders } from '@/lib/auth-headers';
 
 interface Event {
   id: string;
@@ -59,7 +60,7 @@ export default function DashboardEventPage() {
   const fetchEvents = async () => {
     try {
       setLoading(true);
-      const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/admin/events`);
+      const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/admin/events`, { headers: authHeaders() });
       const data = await response.json();
       if (data.success) {
         setEvents(data.data || []);
@@ -201,7 +202,7 @@ export default function DashboardEventPage() {
     try {
       const response = await fetch(url, {
         method,
-        headers: { 'Content-Type': 'application/json' },
+        headers: { 'Content-Type': 'application/json', ...authHeaders() },
         body: JSON.stringify(formData)
       });
 
@@ -224,7 +225,7 @@ export default function DashboardEventPage() {
     try {
       const response = await fetch(
         `${process.env.NEXT_PUBLIC_BACKEND_URL}/api/admin/events/${event.id}`,
-        { method: 'DELETE' }
+        { method: 'DELETE', headers: authHeaders() }
       );
 
       const data = await response.json();
@@ -253,7 +254,7 @@ export default function DashboardEventPage() {
         `${process.env.NEXT_PUBLIC_BACKEND_URL}/api/admin/events/${selectedEvent.id}/comments`,
         {
           method: 'POST',
-          headers: { 'Content-Type': 'application/json' },
+          headers: { 'Content-Type': 'application/json', ...authHeaders() },
           body: JSON.stringify({
             comment_text: replyText,
             parent_id: comment?.id || null
@@ -281,7 +282,7 @@ export default function DashboardEventPage() {
     try {
       const response = await fetch(
         `${process.env.NEXT_PUBLIC_BACKEND_URL}/api/admin/events/${selectedEvent.id}/comments/${commentId}`,
-        { method: 'DELETE' }
+        { method: 'DELETE', headers: authHeaders() }
       );
 
       const data = await response.json();
@@ -23,6 +23,7 @@ import html2canvas from "html2canvas";
 import { Spinner } from "../../../components/ui/ios-spinner";
 import "../dashboard-new.css";
 import "./laporan.css";
+import { authHeaders } from '@/lib/auth-headers';
 
 interface ProductSales {
   product_id: string;
@@ -73,7 +74,8 @@ export default function LaporanPage() {
     try {
       setLoading(true);
       const response = await fetch(
-        `${process.env.NEXT_PUBLIC_BACKEND_URL}/api/reports?start_date=${dateRange.start}&end_date=${dateRange.end}`
+        `${process.env.NEXT_PUBLIC_BACKEND_URL}/api/reports?start_date=${dateRange.start}&end_date=${dateRange.end}`,
+        { headers: authHeaders() }
       );
       const data = await response.json();
       
@@ -5,6 +5,7 @@ import { motion, AnimatePresence } from "framer-motion";
 import { Spinner } from "../../../components/ui/ios-spinner";
 import "../dashboard-new.css";
 import "./menu.css";
+import { authHeaders } from '@/lib/auth-headers';
 
 interface Product {
   id: string;
@@ -119,7 +120,7 @@ export default function DashboardMenuPage() {
     if (showLoading) {
       setLoading(true);
     }
-    fetch(`/api/admin/products`)
+    fetch(`/api/admin/products`, { headers: authHeaders() })
       .then(res => res.json())
       .then(data => {
         console.log('Fetched products:', data);
@@ -145,7 +146,7 @@ export default function DashboardMenuPage() {
 
   const fetchQRISCodes = async () => {
     try {
-      const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/admin/qris`);
+      const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/admin/qris`, { headers: authHeaders() });
       const data = await response.json();
       if (data.success) {
         setQrisCodes(data.data || []);
@@ -441,7 +442,7 @@ export default function DashboardMenuPage() {
     try {
       const response = await fetch(url, {
         method,
-        headers: { 'Content-Type': 'application/json' },
+        headers: { 'Content-Type': 'application/json', ...authHeaders() },
         body: JSON.stringify(payload)
       });
 
@@ -481,7 +482,8 @@ export default function DashboardMenuPage() {
 
     try {
       const response = await fetch(`/api/admin/products/${productToDelete.id}`, {
-        method: 'DELETE'
+        method: 'DELETE',
+        headers: authHeaders()
       });
 
       const data = await response.json();
@@ -510,7 +512,8 @@ export default function DashboardMenuPage() {
       console.log('Toggling product:', product.id, 'Current availability:', product.is_available);
       
       const response = await fetch(`/api/admin/products/${product.id}/toggle`, {
-        method: 'PATCH'
+        method: 'PATCH',
+        headers: authHeaders()
       });
 
       const data = await response.json();
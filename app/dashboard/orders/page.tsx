@@ -5,6 +5,7 @@ import { motion, AnimatePresence } from 'framer-motion';
 import { Spinner } from '../../../components/ui/ios-spinner';
 import '../dashboard-new.css';
 import './orders.css';
+import { authHeaders } from '@/lib/auth-headers';
 
 interface OrderItem {
   id: string;
@@ -67,7 +68,7 @@ export default function DashboardOrdersPage() {
 
   const fetchOrders = async () => {
     try {
-      const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/orders`);
+      const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/orders`, { headers: authHeaders() });
       const data = await response.json();
       
       if (data.success) {
@@ -115,7 +116,7 @@ export default function DashboardOrdersPage() {
     try {
       const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/orders/${orderId}/status`, {
         method: 'PUT',
-        headers: { 'Content-Type': 'application/json' },
+        headers: { 'Content-Type': 'application/json', ...authHeaders() },
         body: JSON.stringify({ status: newStatus })
       });
 
@@ -145,7 +146,7 @@ export default function DashboardOrdersPage() {
     try {
       const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/orders/${orderToCancel.id}/status`, {
         method: 'PUT',
-        headers: { 'Content-Type': 'application/json' },
+        headers: { 'Content-Type': 'application/json', ...authHeaders() },
         body: JSON.stringify({ 
           status: 'cancelled',
           cancellation_reason: cancellationReason 
@@ -223,7 +224,7 @@ export default function DashboardOrdersPage() {
     try {
       const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/orders/${orderToComplete.id}/status`, {
         method: 'PUT',
-        headers: { 'Content-Type': 'application/json' },
+        headers: { 'Content-Type': 'application/json', ...authHeaders() },
         body: JSON.stringify({ 
           status: 'completed',
           delivery_photo: deliveryPhoto,
@@ -261,7 +262,8 @@ export default function DashboardOrdersPage() {
 
     try {
       const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/orders/${orderToDelete.id}`, {
-        method: 'DELETE'
+        method: 'DELETE',
+        headers: authHeaders()
       });
 
       const data = await response.json();
@@ -5,6 +5,7 @@ import { motion, AnimatePresence } from 'framer-motion';
 import { Spinner } from '../../../components/ui/ios-spinner';
 import '../dashboard-new.css';
 import './qris.css';
+import { authHeaders } from '@/lib/auth-headers';
 
 interface QRISCode {
   id: string;
@@ -40,7 +41,7 @@ export default function QRISSettingsPage() {
   const fetchQRISCodes = async () => {
     setLoading(true);
     try {
-      const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/admin/qris`);
+      const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/admin/qris`, { headers: authHeaders() });
       const data = await response.json();
       
       if (data.success) {
@@ -138,7 +139,7 @@ export default function QRISSettingsPage() {
     try {
       const response = await fetch(url, {
         method,
-        headers: { 'Content-Type': 'application/json' },
+        headers: { 'Content-Type': 'application/json', ...authHeaders() },
         body: JSON.stringify(formData)
       });
 
@@ -170,7 +171,8 @@ export default function QRISSettingsPage() {
 
     try {
       const response = await fetch(`${process.env.NEXT_PUBLIC_BACKEND_URL}/api/admin/qris/${qrisToDelete.id}`, {
-        method: 'DELETE'
+        method: 'DELETE',
+        headers: authHeaders()
       });
 
       const data = await response.json();
@@ -54,7 +54,7 @@ export default function MenuPage() {
       console.log('Fetching products from API using fetchAPI helper...');
       setLoading(true);
       try {
-        const res = await fetchAPI('/api/admin/products');
+        const res = await fetchAPI('/api/products?include_sold_out=true');
         const data = await res.json();
 
         console.log('Products fetched:', data);
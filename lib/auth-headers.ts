@@ -0,0 +1,21 @@
+// Admin session token for backend calls
+// The backend requires `Authorization: Bearer <token>` on /api/admin/* and the
+// other admin routes. The token is saved by the login page.
+
+import type { NextRequest } from 'next/server';
+
+// Browser side: header with the token stored at login, empty when logged out
+export function authHeaders(): Record<string, string> {
+  if (typeof window === 'undefined') return {};
+  const token = localStorage.getItem('authToken');
+  return token ? { Authorization: `Bearer ${token}` } : {};
+}
+
+// Proxy side: pass the caller's Authorization header on to the backend,
+// falling back to the auth_token cookie set at login
+export function forwardedAuth(request: NextRequest): Record<string, string> {
+  const header = request.headers.get('authorization');
+  if (header) return { Authorization: header };
+  const token = request.cookies.get('auth_token')?.value;
+  return token ? { Authorization: `Bearer ${token}` } : {};
+}
@@ -1,5 +1,7 @@
 // API helper for fetching from backend
-// Directly calls backend API (no proxy needed)
+// Directly calls backend API (no proxy needed), with the admin token when logged in
+
+import { authHeaders } from './auth-headers';
 
 export async function fetchAPI(endpoint: string, options: RequestInit = {}) {
   // Get backend URL from environment
@@ -11,6 +13,7 @@ export async function fetchAPI(endpoint: string, options: RequestInit = {}) {
   
   const headers = {
     'Content-Type': 'application/json',
+    ...authHeaders(),
     ...options.headers,
   };
   